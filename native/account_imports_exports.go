@@ -0,0 +1,222 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// lastAppliedAccountConfig tracks the export/import set last applied to
+// each account by ApplyAccountConfig, so a later call can diff against it
+// to report what changed and flag entries that were dropped.
+var (
+	accountConfigMu       sync.Mutex
+	lastAppliedAccountCfg = make(map[string]AccountConfig)
+)
+
+// accountConfigDiff summarizes what ApplyAccountConfig changed, so a
+// caller can confirm the reconciliation took effect rather than trusting
+// the request was applied blindly.
+type accountConfigDiff struct {
+	ExportsAdded   []string `json:"exports_added"`
+	ExportsRemoved []string `json:"exports_removed"`
+	ImportsAdded   []string `json:"imports_added"`
+	ImportsRemoved []string `json:"imports_removed"`
+}
+
+func exportKey(e ExportConfig) string { return e.Type + ":" + e.Subject }
+func importKey(i ImportConfig) string { return i.Type + ":" + i.Account + ":" + i.Subject }
+
+// responseTypeFor maps our JSON response_type string to nats-server's
+// service response type enum, defaulting to Singleton.
+func responseTypeFor(name string) server.ServiceRespType {
+	switch name {
+	case "stream":
+		return server.Streamed
+	case "chunked":
+		return server.Chunked
+	default:
+		return server.Singleton
+	}
+}
+
+// applyExport wires a single export onto acc per ExportConfig.Type,
+// restricting it to Accounts when TokenRequired is set.
+func applyExport(srv *server.Server, acc *server.Account, export ExportConfig) error {
+	var authAccounts []*server.Account
+	if export.TokenRequired {
+		for _, pub := range export.Accounts {
+			remote, err := srv.LookupAccount(pub)
+			if err != nil {
+				return fmt.Errorf("export %q: account %q not found: %w", export.Subject, pub, err)
+			}
+			authAccounts = append(authAccounts, remote)
+		}
+	}
+
+	switch export.Type {
+	case "stream":
+		if err := acc.AddStreamExport(export.Subject, authAccounts); err != nil {
+			return fmt.Errorf("export %q: %w", export.Subject, err)
+		}
+	case "service":
+		if err := acc.AddServiceExportWithResponse(export.Subject, responseTypeFor(export.ResponseType), authAccounts); err != nil {
+			return fmt.Errorf("export %q: %w", export.Subject, err)
+		}
+		if export.Latency.Sampling > 0 {
+			if err := acc.TrackServiceExportWithSampling(export.Subject, export.Latency.Subject, export.Latency.Sampling); err != nil {
+				return fmt.Errorf("export %q: failed to enable latency tracking: %w", export.Subject, err)
+			}
+		}
+		if export.AllowTrace {
+			// Best-effort: older server versions may not support trace
+			// propagation on exports (skip if unsupported).
+			_ = acc.SetServiceExportAllowTrace(export.Subject, true)
+		}
+	default:
+		return fmt.Errorf("export %q: unknown type %q", export.Subject, export.Type)
+	}
+
+	return nil
+}
+
+// applyImport wires a single import onto acc per ImportConfig.Type,
+// sourced from the remote account named by Account.
+func applyImport(srv *server.Server, acc *server.Account, imp ImportConfig) error {
+	remote, err := srv.LookupAccount(imp.Account)
+	if err != nil {
+		return fmt.Errorf("import %q: account %q not found: %w", imp.Subject, imp.Account, err)
+	}
+
+	claim := &jwt.Import{
+		Name:    imp.Subject,
+		Subject: jwt.Subject(imp.Subject),
+		Account: imp.Account,
+		Token:   imp.Token,
+		To:      jwt.Subject(imp.LocalSubject),
+		Share:   imp.Share,
+	}
+
+	switch imp.Type {
+	case "stream":
+		claim.Type = jwt.Stream
+		if err := acc.AddStreamImportWithClaim(remote, claim); err != nil {
+			return fmt.Errorf("import %q: %w", imp.Subject, err)
+		}
+	case "service":
+		claim.Type = jwt.Service
+		if err := acc.AddServiceImportWithClaim(remote, claim); err != nil {
+			return fmt.Errorf("import %q: %w", imp.Subject, err)
+		}
+	default:
+		return fmt.Errorf("import %q: unknown type %q", imp.Subject, imp.Type)
+	}
+
+	return nil
+}
+
+// ApplyAccountConfig reconciles the account named in accountJson's "name"
+// field with its requested exports/imports: every export/import present is
+// (re)applied via the matching Add*Export/Add*ImportWithClaim call, and
+// entries present in the account's previously-applied config but absent
+// now are reported as removed. nats-server has no public API to retract an
+// individual export/import from a live account, so a removal only takes
+// full effect on the account's next reload/restart; ApplyAccountConfig
+// still reports it so callers know reconciliation is pending.
+//
+//export ApplyAccountConfig
+func ApplyAccountConfig(accountJson *C.char) *C.char {
+	if accountJson == nil {
+		return C.CString("ERROR: configuration cannot be null")
+	}
+
+	var config AccountConfig
+	if err := json.Unmarshal([]byte(C.GoString(accountJson)), &config); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse account configuration: %v", err))
+	}
+	if config.Name == "" {
+		return C.CString("ERROR: account name cannot be empty")
+	}
+
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	acc, err := srv.LookupAccount(config.Name)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Account not found: %v", err))
+	}
+
+	for _, export := range config.Exports {
+		if err := applyExport(srv, acc, export); err != nil {
+			return C.CString(fmt.Sprintf("ERROR: %v", err))
+		}
+	}
+	for _, imp := range config.Imports {
+		if err := applyImport(srv, acc, imp); err != nil {
+			return C.CString(fmt.Sprintf("ERROR: %v", err))
+		}
+	}
+
+	accountConfigMu.Lock()
+	previous, hadPrevious := lastAppliedAccountCfg[config.Name]
+	lastAppliedAccountCfg[config.Name] = config
+	accountConfigMu.Unlock()
+
+	diff := accountConfigDiff{}
+	prevExports := make(map[string]bool, len(previous.Exports))
+	for _, export := range previous.Exports {
+		prevExports[exportKey(export)] = true
+	}
+	prevImports := make(map[string]bool, len(previous.Imports))
+	for _, imp := range previous.Imports {
+		prevImports[importKey(imp)] = true
+	}
+
+	newExports := make(map[string]bool, len(config.Exports))
+	for _, export := range config.Exports {
+		key := exportKey(export)
+		newExports[key] = true
+		if !hadPrevious || !prevExports[key] {
+			diff.ExportsAdded = append(diff.ExportsAdded, key)
+		}
+	}
+	newImports := make(map[string]bool, len(config.Imports))
+	for _, imp := range config.Imports {
+		key := importKey(imp)
+		newImports[key] = true
+		if !hadPrevious || !prevImports[key] {
+			diff.ImportsAdded = append(diff.ImportsAdded, key)
+		}
+	}
+	if hadPrevious {
+		for _, export := range previous.Exports {
+			key := exportKey(export)
+			if !newExports[key] {
+				diff.ExportsRemoved = append(diff.ExportsRemoved, key)
+			}
+		}
+		for _, imp := range previous.Imports {
+			key := importKey(imp)
+			if !newImports[key] {
+				diff.ImportsRemoved = append(diff.ImportsRemoved, key)
+			}
+		}
+	}
+
+	jsonBytes, err := json.Marshal(diff)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal diff: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
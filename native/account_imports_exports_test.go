@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func registerTestAccount(t *testing.T, name string) {
+	t.Helper()
+	nameCStr := cString(name)
+	defer cFree(nameCStr)
+	response := goStringFree(RegisterAccount(nameCStr))
+	if isErrorResponse(response) {
+		t.Fatalf("Failed to register account %q: %s", name, response)
+	}
+}
+
+// Test ApplyAccountConfig wires a stream export on one account and a
+// matching stream import on another, and reports both as added.
+func TestApplyAccountConfig_StreamExportImport(t *testing.T) {
+	port := 14400
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	registerTestAccount(t, "PRODUCER")
+	registerTestAccount(t, "CONSUMER")
+
+	exportCfg := cString(`{"name":"PRODUCER","exports":[{"subject":"events.>","type":"stream"}]}`)
+	defer cFree(exportCfg)
+	exportResponse := goStringFree(ApplyAccountConfig(exportCfg))
+	if isErrorResponse(exportResponse) {
+		t.Fatalf("Expected success applying export config, got: %s", exportResponse)
+	}
+
+	importJson := fmt.Sprintf(`{"name":"CONSUMER","imports":[{"account":"PRODUCER","subject":"events.>","local_subject":"events.>","type":"stream"}]}`)
+	importCfg := cString(importJson)
+	defer cFree(importCfg)
+	importResponse := goStringFree(ApplyAccountConfig(importCfg))
+	if isErrorResponse(importResponse) {
+		t.Fatalf("Expected success applying import config, got: %s", importResponse)
+	}
+
+	var diff accountConfigDiff
+	if err := json.Unmarshal([]byte(importResponse), &diff); err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+	if len(diff.ImportsAdded) != 1 || diff.ImportsAdded[0] != "stream:PRODUCER:events.>" {
+		t.Errorf("Expected one stream import added, got: %v", diff.ImportsAdded)
+	}
+}
+
+// Test ApplyAccountConfig reports a previously-applied export as removed
+// once a later call omits it.
+func TestApplyAccountConfig_ReportsRemovedExport(t *testing.T) {
+	port := 14401
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	registerTestAccount(t, "PRODUCER2")
+
+	firstCfg := cString(`{"name":"PRODUCER2","exports":[{"subject":"a.>","type":"stream"},{"subject":"b.>","type":"stream"}]}`)
+	defer cFree(firstCfg)
+	firstResult := ApplyAccountConfig(firstCfg)
+	cFree(firstResult)
+
+	secondCfg := cString(`{"name":"PRODUCER2","exports":[{"subject":"a.>","type":"stream"}]}`)
+	defer cFree(secondCfg)
+	secondResponse := goStringFree(ApplyAccountConfig(secondCfg))
+	if isErrorResponse(secondResponse) {
+		t.Fatalf("Expected success applying second config, got: %s", secondResponse)
+	}
+
+	var diff accountConfigDiff
+	if err := json.Unmarshal([]byte(secondResponse), &diff); err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+	if len(diff.ExportsRemoved) != 1 || diff.ExportsRemoved[0] != "stream:b.>" {
+		t.Errorf("Expected export 'stream:b.>' reported removed, got: %v", diff.ExportsRemoved)
+	}
+}
+
+// Test that reapplying an unchanged config reports no exports/imports as
+// added - only entries newly absent from the previous call should ever
+// show up in ExportsAdded/ImportsAdded.
+func TestApplyAccountConfig_UnchangedConfigReportsNoneAdded(t *testing.T) {
+	port := 14402
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	registerTestAccount(t, "PRODUCER3")
+
+	cfg := cString(`{"name":"PRODUCER3","exports":[{"subject":"c.>","type":"stream"}]}`)
+	defer cFree(cfg)
+
+	firstResult := ApplyAccountConfig(cfg)
+	cFree(firstResult)
+
+	secondResponse := goStringFree(ApplyAccountConfig(cfg))
+	if isErrorResponse(secondResponse) {
+		t.Fatalf("Expected success reapplying unchanged config, got: %s", secondResponse)
+	}
+
+	var diff accountConfigDiff
+	if err := json.Unmarshal([]byte(secondResponse), &diff); err != nil {
+		t.Fatalf("Failed to parse diff: %v", err)
+	}
+	if len(diff.ExportsAdded) != 0 {
+		t.Errorf("Expected no exports added on unchanged reapply, got: %v", diff.ExportsAdded)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/nats-io/jwt/v2"
+)
+
+// accountProvisioning tracks the decentralized-auth state needed to bring
+// the embedded server to parity with standalone nats-server: the trusted
+// operator public key, and the last-applied claims per account so that
+// RevokeUser can mutate and re-apply them without a round trip to nsc.
+var (
+	accountProvisioningMu sync.Mutex
+	trustedOperatorKey    string
+	lastAccountClaims     = make(map[string]*jwt.AccountClaims)
+)
+
+//export SetOperatorTrust
+func SetOperatorTrust(operatorJwt *C.char) *C.char {
+	if operatorJwt == nil {
+		return C.CString("ERROR: operator JWT cannot be null")
+	}
+
+	claims, err := jwt.DecodeOperatorClaims(C.GoString(operatorJwt))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to decode operator JWT: %v", err))
+	}
+
+	accountProvisioningMu.Lock()
+	trustedOperatorKey = claims.Subject
+	accountProvisioningMu.Unlock()
+
+	return C.CString("OK")
+}
+
+// verifyAgainstOperatorTrust checks the account claims' issuer against the
+// configured operator trust, when one has been set via SetOperatorTrust.
+func verifyAgainstOperatorTrust(claims *jwt.AccountClaims) error {
+	accountProvisioningMu.Lock()
+	operatorKey := trustedOperatorKey
+	accountProvisioningMu.Unlock()
+
+	if operatorKey == "" {
+		return nil
+	}
+	if claims.Issuer != operatorKey {
+		return fmt.Errorf("account JWT issuer %q is not the trusted operator", claims.Issuer)
+	}
+	return nil
+}
+
+//export RegisterAccountJWT
+func RegisterAccountJWT(accountJwt *C.char, accountNkey *C.char) *C.char {
+	if accountJwt == nil {
+		return C.CString("ERROR: account JWT cannot be null")
+	}
+
+	claims, err := jwt.DecodeAccountClaims(C.GoString(accountJwt))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to decode account JWT: %v", err))
+	}
+
+	if accountNkey != nil {
+		if nkey := C.GoString(accountNkey); nkey != "" && nkey != claims.Subject {
+			return C.CString("ERROR: account nkey does not match JWT subject")
+		}
+	}
+
+	if err := verifyAgainstOperatorTrust(claims); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	acc, err := srv.RegisterAccount(claims.Subject)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to register account: %v", err))
+	}
+	srv.UpdateAccountClaims(acc, claims)
+
+	accountProvisioningMu.Lock()
+	lastAccountClaims[claims.Subject] = claims
+	accountProvisioningMu.Unlock()
+
+	response := map[string]interface{}{
+		"account":   acc.GetName(),
+		"jetstream": acc.JetStreamEnabled(),
+	}
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal account info: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+//export UpdateAccountJWT
+func UpdateAccountJWT(accountJwt *C.char) *C.char {
+	if accountJwt == nil {
+		return C.CString("ERROR: account JWT cannot be null")
+	}
+
+	claims, err := jwt.DecodeAccountClaims(C.GoString(accountJwt))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to decode account JWT: %v", err))
+	}
+
+	if err := verifyAgainstOperatorTrust(claims); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	acc, err := srv.LookupAccount(claims.Subject)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Account not found: %v", err))
+	}
+	srv.UpdateAccountClaims(acc, claims)
+
+	accountProvisioningMu.Lock()
+	lastAccountClaims[claims.Subject] = claims
+	accountProvisioningMu.Unlock()
+
+	return C.CString("OK")
+}
+
+//export RevokeUser
+func RevokeUser(account *C.char, userNkey *C.char) *C.char {
+	if account == nil || userNkey == nil {
+		return C.CString("ERROR: account and user nkey cannot be null")
+	}
+
+	acctKey := C.GoString(account)
+	nkey := C.GoString(userNkey)
+
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	acc, err := srv.LookupAccount(acctKey)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Account not found: %v", err))
+	}
+
+	accountProvisioningMu.Lock()
+	defer accountProvisioningMu.Unlock()
+
+	claims, exists := lastAccountClaims[acctKey]
+	if !exists {
+		return C.CString("ERROR: No claims on file for account; register it via RegisterAccountJWT first")
+	}
+
+	claims.Revoke(nkey)
+	srv.UpdateAccountClaims(acc, claims)
+
+	return C.CString("OK")
+}
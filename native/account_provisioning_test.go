@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+)
+
+// Test RegisterAccountJWT installs an account signed by a trusted operator
+func TestRegisterAccountJWT_Success(t *testing.T) {
+	port := 14290
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	operatorKey, err := nkeys.CreateOperator()
+	if err != nil {
+		t.Fatalf("Failed to create operator key: %v", err)
+	}
+	operatorPub, _ := operatorKey.PublicKey()
+
+	operatorClaims := jwt.NewOperatorClaims(operatorPub)
+	operatorJWT, err := operatorClaims.Encode(operatorKey)
+	if err != nil {
+		t.Fatalf("Failed to encode operator JWT: %v", err)
+	}
+
+	accountKey, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatalf("Failed to create account key: %v", err)
+	}
+	accountPub, _ := accountKey.PublicKey()
+
+	accountClaims := jwt.NewAccountClaims(accountPub)
+	accountClaims.Name = "provisioned"
+	accountJWT, err := accountClaims.Encode(operatorKey)
+	if err != nil {
+		t.Fatalf("Failed to encode account JWT: %v", err)
+	}
+
+	operatorCStr := cString(operatorJWT)
+	defer cFree(operatorCStr)
+	if resp := SetOperatorTrust(operatorCStr); isErrorResponse(goString(resp)) {
+		t.Fatalf("Expected success setting operator trust, got: %s", goString(resp))
+	}
+
+	accountCStr := cString(accountJWT)
+	defer cFree(accountCStr)
+	nkeyCStr := cString(accountPub)
+	defer cFree(nkeyCStr)
+
+	response := goStringFree(RegisterAccountJWT(accountCStr, nkeyCStr))
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success, got error: %s", response)
+	}
+}
+
+// Test RegisterAccountJWT rejects a JWT from an untrusted operator
+func TestRegisterAccountJWT_UntrustedOperator(t *testing.T) {
+	port := 14291
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	trustedOperator, _ := nkeys.CreateOperator()
+	trustedPub, _ := trustedOperator.PublicKey()
+	operatorJWT, _ := jwt.NewOperatorClaims(trustedPub).Encode(trustedOperator)
+
+	operatorCStr := cString(operatorJWT)
+	defer cFree(operatorCStr)
+	SetOperatorTrust(operatorCStr)
+
+	otherOperator, _ := nkeys.CreateOperator()
+	accountKey, _ := nkeys.CreateAccount()
+	accountPub, _ := accountKey.PublicKey()
+	accountJWT, _ := jwt.NewAccountClaims(accountPub).Encode(otherOperator)
+
+	accountCStr := cString(accountJWT)
+	defer cFree(accountCStr)
+
+	response := goStringFree(RegisterAccountJWT(accountCStr, nil))
+
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error for account JWT signed by an untrusted operator")
+	}
+}
+
+// Test RevokeUser requires a prior RegisterAccountJWT to have claims on file
+func TestRevokeUser_NoClaimsOnFile(t *testing.T) {
+	port := 14292
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	srv.RegisterAccount("UNREGISTERED_VIA_JWT")
+
+	account := cString("UNREGISTERED_VIA_JWT")
+	defer cFree(account)
+	userNkey := cString("UABC123")
+	defer cFree(userNkey)
+
+	response := goStringFree(RevokeUser(account, userNkey))
+
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error revoking a user for an account with no claims on file")
+	}
+}
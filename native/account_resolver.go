@@ -0,0 +1,245 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nkeys"
+)
+
+// AccountResolverConfig is ConfigureAccountResolver's input shape, mirroring
+// ResolverConfig's fields for the boot-time path in nats-bindings.go, but
+// applied to an already-running server via ReloadOptions instead of at
+// NewServer time.
+type AccountResolverConfig struct {
+	Preload     []string `json:"preload"` // account JWTs to seed a mem resolver with
+	URL         string   `json:"url"`     // base URL for a "url" resolver
+	Dir         string   `json:"dir"`     // directory for a "nats" (NATS-synced directory) resolver
+	AllowDelete bool     `json:"allow_delete"`
+	Timeout     int      `json:"timeout"` // seconds
+}
+
+// ConfigureAccountResolver swaps the current server's account resolver for
+// kind ("mem", "url", or "nats"), so a decentralized-auth deployment can
+// switch resolution strategy - e.g. from a mem resolver seeded for tests to
+// a directory resolver synced over $SYS.REQ.CLAIMS - without a full restart.
+//
+//export ConfigureAccountResolver
+func ConfigureAccountResolver(kind *C.char, config *C.char) *C.char {
+	if kind == nil {
+		return C.CString("ERROR: resolver kind cannot be null")
+	}
+	kindStr := C.GoString(kind)
+
+	var cfg AccountResolverConfig
+	if config != nil {
+		if cfgStr := C.GoString(config); cfgStr != "" {
+			if err := json.Unmarshal([]byte(cfgStr), &cfg); err != nil {
+				return C.CString(fmt.Sprintf("ERROR: Failed to parse resolver configuration: %v", err))
+			}
+		}
+	}
+
+	var resolver server.AccountResolver
+	switch kindStr {
+	case "mem":
+		memResolver := &server.MemAccResolver{}
+		for _, accountJWT := range cfg.Preload {
+			claims, err := jwt.DecodeAccountClaims(accountJWT)
+			if err != nil {
+				return C.CString(fmt.Sprintf("ERROR: Failed to decode preloaded account JWT: %v", err))
+			}
+			memResolver.Store(claims.Subject, accountJWT)
+		}
+		resolver = memResolver
+
+	case "url":
+		if cfg.URL == "" {
+			return C.CString("ERROR: url resolver requires a url")
+		}
+		urlResolver, err := server.NewURLAccResolver(cfg.URL)
+		if err != nil {
+			return C.CString(fmt.Sprintf("ERROR: Failed to create url resolver: %v", err))
+		}
+		resolver = urlResolver
+
+	case "nats":
+		// Note: nats-server has no resolver kind distinct from a directory
+		// resolver - a "nats" kind here is a DirAccResolver, which starts
+		// participating in the $SYS.REQ.CLAIMS sync protocol on its own once
+		// it's attached to a running server, the same mechanism PushAccountJWT
+		// and DeleteAccountJWT in jwt_lifecycle.go talk to.
+		ttl := time.Duration(cfg.Timeout) * time.Second
+		deleteType := server.NoDelete
+		if cfg.AllowDelete {
+			deleteType = server.RenameDeleted
+		}
+		dirResolver, err := server.NewDirAccResolver(cfg.Dir, 0, ttl, deleteType)
+		if err != nil {
+			return C.CString(fmt.Sprintf("ERROR: Failed to create NATS-synced resolver: %v", err))
+		}
+		resolver = dirResolver
+
+	default:
+		return C.CString(fmt.Sprintf("ERROR: Unknown resolver kind %q", kindStr))
+	}
+
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, exists := natsServers[currentPort]
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+	opts, exists := natsServerOpts[currentPort]
+	if !exists || opts == nil {
+		return C.CString("ERROR: No options on file for the current server")
+	}
+
+	opts.AccountResolver = resolver
+	if err := srv.ReloadOptions(opts); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to reload account resolver: %v", err))
+	}
+
+	return C.CString("OK")
+}
+
+// UpdateAccountClaims is UpdateAccountJWT's counterpart in the resolver
+// bindings surface, kept as a separate exported name so host applications
+// built against the resolver API don't also need to know about the
+// account_provisioning.go entry point that does the same work.
+//
+//export UpdateAccountClaims
+func UpdateAccountClaims(accountJwt *C.char) *C.char {
+	return UpdateAccountJWT(accountJwt)
+}
+
+// LookupAccountByNkey looks up an account by its nkey public key rather
+// than by name, the identity decentralized-auth deployments actually use -
+// RegisterAccount/LookupAccount in nats-bindings.go predate JWT/nkey support
+// and only ever dealt with human-chosen account names.
+//
+//export LookupAccountByNkey
+func LookupAccountByNkey(pubKey *C.char) *C.char {
+	if pubKey == nil {
+		return C.CString("ERROR: public key cannot be null")
+	}
+	pub := C.GoString(pubKey)
+	if pub == "" {
+		return C.CString("ERROR: public key cannot be empty")
+	}
+
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	account, err := srv.LookupAccount(pub)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Account not found: %v", err))
+	}
+
+	response := map[string]interface{}{
+		"account":       account.GetName(),
+		"connections":   account.NumConnections(),
+		"subscriptions": account.RoutedSubs(),
+		"jetstream":     account.JetStreamEnabled(),
+	}
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal account info: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// AccountJWTLimits is GenerateAccountJWT's limits shape, mirroring
+// AccountConfig's connection/subscription/data/payload limits so the same
+// mental model applies whether an account is registered locally or granted
+// via a signed JWT.
+type AccountJWTLimits struct {
+	MaxConnections   int   `json:"max_connections"`
+	MaxSubscriptions int   `json:"max_subscriptions"`
+	MaxData          int64 `json:"max_data"`
+	MaxPayload       int64 `json:"max_payload"`
+}
+
+// GenerateAccountJWT mints a fresh account identity and signs it with
+// issuerNkeySeed - an operator seed, or an account's own signing key, both
+// of which nkeys.FromSeed accepts identically - returning the encoded JWT
+// alongside the new account's own seed and public key.
+//
+//export GenerateAccountJWT
+func GenerateAccountJWT(name *C.char, issuerNkeySeed *C.char, limits *C.char) *C.char {
+	if issuerNkeySeed == nil {
+		return C.CString("ERROR: issuer nkey seed cannot be null")
+	}
+
+	issuerKP, err := nkeys.FromSeed([]byte(C.GoString(issuerNkeySeed)))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Invalid issuer nkey seed: %v", err))
+	}
+
+	accountKP, err := nkeys.CreateAccount()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to create account key: %v", err))
+	}
+	accountPub, err := accountKP.PublicKey()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to derive account public key: %v", err))
+	}
+	accountSeed, err := accountKP.Seed()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to derive account seed: %v", err))
+	}
+
+	claims := jwt.NewAccountClaims(accountPub)
+	if name != nil {
+		claims.Name = C.GoString(name)
+	}
+
+	if limits != nil {
+		if limitsStr := C.GoString(limits); limitsStr != "" {
+			var lim AccountJWTLimits
+			if err := json.Unmarshal([]byte(limitsStr), &lim); err != nil {
+				return C.CString(fmt.Sprintf("ERROR: Failed to parse limits: %v", err))
+			}
+			if lim.MaxConnections > 0 {
+				claims.Limits.Conn = int64(lim.MaxConnections)
+			}
+			if lim.MaxSubscriptions > 0 {
+				claims.Limits.Subs = int64(lim.MaxSubscriptions)
+			}
+			if lim.MaxData > 0 {
+				claims.Limits.Data = lim.MaxData
+			}
+			if lim.MaxPayload > 0 {
+				claims.Limits.Payload = lim.MaxPayload
+			}
+		}
+	}
+
+	accountJWT, err := claims.Encode(issuerKP)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to encode account JWT: %v", err))
+	}
+
+	accountProvisioningMu.Lock()
+	lastAccountClaims[accountPub] = claims
+	accountProvisioningMu.Unlock()
+
+	response := keyPairResponse{JWT: accountJWT, Seed: string(accountSeed), Pub: accountPub}
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal account JWT: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+)
+
+// Test ConfigureAccountResolver installs a mem resolver preloaded with an
+// account JWT, and that the account becomes resolvable afterward.
+func TestConfigureAccountResolver_MemPreload(t *testing.T) {
+	port := 14470
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	accountKey, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatalf("Failed to create account key: %v", err)
+	}
+	accountPub, _ := accountKey.PublicKey()
+	accountJWT, err := jwt.NewAccountClaims(accountPub).Encode(accountKey)
+	if err != nil {
+		t.Fatalf("Failed to encode account JWT: %v", err)
+	}
+
+	cfgJSON, err := json.Marshal(AccountResolverConfig{Preload: []string{accountJWT}})
+	if err != nil {
+		t.Fatalf("Failed to marshal resolver config: %v", err)
+	}
+
+	kind := cString("mem")
+	defer cFree(kind)
+	cfg := cString(string(cfgJSON))
+	defer cFree(cfg)
+
+	response := goStringFree(ConfigureAccountResolver(kind, cfg))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success configuring mem resolver, got: %s", response)
+	}
+}
+
+// Test ConfigureAccountResolver rejects an unknown resolver kind.
+func TestConfigureAccountResolver_UnknownKind(t *testing.T) {
+	port := 14471
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	kind := cString("bogus")
+	defer cFree(kind)
+
+	response := goStringFree(ConfigureAccountResolver(kind, nil))
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error for an unknown resolver kind")
+	}
+}
+
+// Test GenerateAccountJWT mints an account JWT signed by the given issuer
+// seed, carrying the requested limits.
+func TestGenerateAccountJWT_AppliesLimits(t *testing.T) {
+	operatorKP, err := nkeys.CreateOperator()
+	if err != nil {
+		t.Fatalf("Failed to create operator key: %v", err)
+	}
+	operatorSeed, _ := operatorKP.Seed()
+	operatorPub, _ := operatorKP.PublicKey()
+
+	seedCStr := cString(string(operatorSeed))
+	defer cFree(seedCStr)
+	nameCStr := cString("billing")
+	defer cFree(nameCStr)
+	limitsCStr := cString(`{"max_connections":10,"max_data":2048}`)
+	defer cFree(limitsCStr)
+
+	response := goStringFree(GenerateAccountJWT(nameCStr, seedCStr, limitsCStr))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success generating account JWT, got: %s", response)
+	}
+
+	var parsed keyPairResponse
+	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
+		t.Fatalf("Failed to parse GenerateAccountJWT response: %v", err)
+	}
+
+	claims, err := jwt.DecodeAccountClaims(parsed.JWT)
+	if err != nil {
+		t.Fatalf("Failed to decode generated account JWT: %v", err)
+	}
+	if claims.Issuer != operatorPub {
+		t.Errorf("Expected account JWT issuer %q to be the operator %q", claims.Issuer, operatorPub)
+	}
+	if claims.Name != "billing" {
+		t.Errorf("Expected account name 'billing', got: %s", claims.Name)
+	}
+	if claims.Limits.Conn != 10 {
+		t.Errorf("Expected max connections 10, got: %d", claims.Limits.Conn)
+	}
+	if claims.Limits.Data != 2048 {
+		t.Errorf("Expected max data 2048, got: %d", claims.Limits.Data)
+	}
+}
+
+// Test LookupAccountByNkey surfaces a clear error for an unregistered key.
+func TestLookupAccountByNkey_NotFound(t *testing.T) {
+	port := 14472
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	accountKey, _ := nkeys.CreateAccount()
+	accountPub, _ := accountKey.PublicKey()
+
+	pubCStr := cString(accountPub)
+	defer cFree(pubCStr)
+
+	response := goStringFree(LookupAccountByNkey(pubCStr))
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error looking up an account that was never registered")
+	}
+}
+
+// Test UpdateAccountClaims surfaces a clear error when no server is running,
+// mirroring PushAccountJWT's ServerNotRunning coverage for the same family.
+func TestUpdateAccountClaims_ServerNotRunning(t *testing.T) {
+	serverMu.Lock()
+	setCurrentPortLocked(0)
+	serverMu.Unlock()
+
+	accountKey, _ := nkeys.CreateAccount()
+	accountPub, _ := accountKey.PublicKey()
+	accountJWT, _ := jwt.NewAccountClaims(accountPub).Encode(accountKey)
+
+	jwtCStr := cString(accountJWT)
+	defer cFree(jwtCStr)
+
+	response := goStringFree(UpdateAccountClaims(jwtCStr))
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error updating account claims with no server running")
+	}
+}
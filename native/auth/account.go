@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+)
+
+// AccountLimits is the subset of an account's JWT limits this package
+// programs directly - connection count, subscriptions, data and payload
+// caps, and an optional absolute expiry.
+type AccountLimits struct {
+	MaxConnections   int
+	MaxSubscriptions int
+	MaxData          int64
+	MaxPayload       int64
+	Expiry           time.Time
+}
+
+// Account wraps a provisioned account identity and the issuer key needed
+// to re-sign it as its trust chain changes - e.g. adding a signing key or
+// rotating one out.
+type Account struct {
+	name     string
+	kp       nkeys.KeyPair
+	pub      string
+	issuerKP nkeys.KeyPair
+	claims   *jwt.AccountClaims
+	ks       Keystore
+}
+
+func newAccount(name string, kp, issuerKP nkeys.KeyPair, limits AccountLimits, ks Keystore) (*Account, error) {
+	pub, err := kp.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive account public key: %w", err)
+	}
+	seed, err := kp.Seed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive account seed: %w", err)
+	}
+	if err := ks.Put(name, seed); err != nil {
+		return nil, err
+	}
+
+	claims := jwt.NewAccountClaims(pub)
+	claims.Name = name
+	applyAccountLimits(claims, limits)
+
+	return &Account{name: name, kp: kp, pub: pub, issuerKP: issuerKP, claims: claims, ks: ks}, nil
+}
+
+func applyAccountLimits(claims *jwt.AccountClaims, limits AccountLimits) {
+	if limits.MaxConnections > 0 {
+		claims.Limits.Conn = int64(limits.MaxConnections)
+	}
+	if limits.MaxSubscriptions > 0 {
+		claims.Limits.Subs = int64(limits.MaxSubscriptions)
+	}
+	if limits.MaxData > 0 {
+		claims.Limits.Data = limits.MaxData
+	}
+	if limits.MaxPayload > 0 {
+		claims.Limits.Payload = limits.MaxPayload
+	}
+	if !limits.Expiry.IsZero() {
+		claims.Expires = limits.Expiry.Unix()
+	}
+}
+
+// PublicKey returns the account's public identity.
+func (a *Account) PublicKey() string {
+	return a.pub
+}
+
+// JWT encodes and returns the account's current JWT, signed by its issuer.
+func (a *Account) JWT() (string, error) {
+	return a.claims.Encode(a.issuerKP)
+}
+
+// AddSigningKey adds a new signing key to the account, so it can delegate
+// user-JWT issuance without handing out its main identity key, and returns
+// the new signing keypair's public key and seed.
+func (a *Account) AddSigningKey() (pub string, seed string, err error) {
+	signingKP, err := nkeys.CreateAccount()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create signing key: %w", err)
+	}
+	signingPub, err := signingKP.PublicKey()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to derive signing public key: %w", err)
+	}
+	signingSeed, err := signingKP.Seed()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to derive signing seed: %w", err)
+	}
+
+	a.claims.SigningKeys.Add(signingPub)
+	return signingPub, string(signingSeed), nil
+}
+
+// RotateSigningKey removes oldSigningKeyPub from the account's trust chain
+// in the same update that adds its replacement, so there's never a window
+// where both the old and new key are simultaneously trusted.
+func (a *Account) RotateSigningKey(oldSigningKeyPub string) (pub string, seed string, err error) {
+	a.claims.SigningKeys.Remove(oldSigningKeyPub)
+	return a.AddSigningKey()
+}
+
+// IssueUser mints a new user identity signed by this account, with perms
+// applied, and persists its seed to the account's keystore under name.
+func (a *Account) IssueUser(name string, perms UserPermissions) (*User, error) {
+	kp, err := nkeys.CreateUser()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user key: %w", err)
+	}
+	return newUser(name, kp, a.kp, a.pub, perms, a.ks)
+}
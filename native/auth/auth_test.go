@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nats-io/jwt/v2"
+)
+
+// Test that a FileKeystore roundtrips a seed through disk.
+func TestFileKeystore_PutGet(t *testing.T) {
+	ks, err := NewFileKeystore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create keystore: %v", err)
+	}
+
+	if err := ks.Put("widget", []byte("SEED-DATA")); err != nil {
+		t.Fatalf("Failed to put seed: %v", err)
+	}
+
+	seed, err := ks.Get("widget")
+	if err != nil {
+		t.Fatalf("Failed to get seed: %v", err)
+	}
+	if string(seed) != "SEED-DATA" {
+		t.Errorf("Expected seed 'SEED-DATA', got: %s", seed)
+	}
+}
+
+// Test the full operator -> account -> user chain mints JWTs that verify
+// against each other.
+func TestOperatorKit_IssueAccountAndUser(t *testing.T) {
+	ks := NewMemKeystore()
+	operator, err := NewOperatorKit("test-operator", ks)
+	if err != nil {
+		t.Fatalf("Failed to create operator kit: %v", err)
+	}
+
+	account, err := operator.IssueAccount("TENANT_A", AccountLimits{MaxConnections: 10})
+	if err != nil {
+		t.Fatalf("Failed to issue account: %v", err)
+	}
+
+	accountJWT, err := account.JWT()
+	if err != nil {
+		t.Fatalf("Failed to encode account JWT: %v", err)
+	}
+	accountClaims, err := jwt.DecodeAccountClaims(accountJWT)
+	if err != nil {
+		t.Fatalf("Failed to decode account JWT: %v", err)
+	}
+	if accountClaims.Limits.Conn != 10 {
+		t.Errorf("Expected account max connections 10, got: %d", accountClaims.Limits.Conn)
+	}
+	if accountClaims.Issuer != operator.PublicKey() {
+		t.Errorf("Expected account issuer %q, got: %q", operator.PublicKey(), accountClaims.Issuer)
+	}
+
+	user, err := account.IssueUser("alice", UserPermissions{AllowedPublish: []string{"orders.>"}})
+	if err != nil {
+		t.Fatalf("Failed to issue user: %v", err)
+	}
+
+	userJWT, err := user.JWT()
+	if err != nil {
+		t.Fatalf("Failed to encode user JWT: %v", err)
+	}
+	userClaims, err := jwt.DecodeUserClaims(userJWT)
+	if err != nil {
+		t.Fatalf("Failed to decode user JWT: %v", err)
+	}
+	if userClaims.IssuerAccount != account.PublicKey() {
+		t.Errorf("Expected user issuer account %q, got: %q", account.PublicKey(), userClaims.IssuerAccount)
+	}
+	if !userClaims.Pub.Allow.Contains("orders.>") {
+		t.Error("Expected user to be allowed to publish to 'orders.>'")
+	}
+
+	creds, err := user.Creds()
+	if err != nil {
+		t.Fatalf("Failed to format user creds: %v", err)
+	}
+	if creds == "" {
+		t.Error("Expected non-empty .creds body")
+	}
+}
+
+// Test RotateSigningKey removes the old key and adds a new one in the same
+// update.
+func TestAccount_RotateSigningKey(t *testing.T) {
+	ks := NewMemKeystore()
+	operator, err := NewOperatorKit("test-operator", ks)
+	if err != nil {
+		t.Fatalf("Failed to create operator kit: %v", err)
+	}
+	account, err := operator.IssueAccount("TENANT_B", AccountLimits{})
+	if err != nil {
+		t.Fatalf("Failed to issue account: %v", err)
+	}
+
+	oldPub, _, err := account.AddSigningKey()
+	if err != nil {
+		t.Fatalf("Failed to add signing key: %v", err)
+	}
+
+	newPub, _, err := account.RotateSigningKey(oldPub)
+	if err != nil {
+		t.Fatalf("Failed to rotate signing key: %v", err)
+	}
+
+	accountJWT, err := account.JWT()
+	if err != nil {
+		t.Fatalf("Failed to encode account JWT: %v", err)
+	}
+	claims, err := jwt.DecodeAccountClaims(accountJWT)
+	if err != nil {
+		t.Fatalf("Failed to decode account JWT: %v", err)
+	}
+	if claims.SigningKeys.Contains(oldPub) {
+		t.Error("Expected old signing key to be removed")
+	}
+	if !claims.SigningKeys.Contains(newPub) {
+		t.Error("Expected new signing key to be present")
+	}
+}
+
+// Test BuildResolver(ResolverMem) preloads the given account JWTs.
+func TestBuildResolver_MemPreload(t *testing.T) {
+	resolver, err := BuildResolver(ResolverConfig{
+		Kind:    ResolverMem,
+		Preload: map[string]string{"ACCTPUB": "some-jwt"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to build resolver: %v", err)
+	}
+	jwtBytes, err := resolver.Fetch("ACCTPUB")
+	if err != nil {
+		t.Fatalf("Failed to fetch preloaded account: %v", err)
+	}
+	if jwtBytes != "some-jwt" {
+		t.Errorf("Expected preloaded JWT 'some-jwt', got: %s", jwtBytes)
+	}
+}
+
+// Test Bootstrap provisions an operator, account, and resolver from a JSON
+// config file, ready to hand to server.NewServer.
+func TestBootstrap_FromConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "bootstrap.json")
+	config := BootstrapConfig{
+		OperatorName: "test-operator",
+		KeystoreDir:  filepath.Join(dir, "keys"),
+		Accounts: []BootstrapAccountConfig{
+			{
+				Name:           "TENANT_A",
+				MaxConnections: 5,
+				Users: []BootstrapUserConfig{
+					{Name: "alice", AllowedPublish: []string{"orders.>"}},
+				},
+			},
+		},
+	}
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		t.Fatalf("Failed to marshal bootstrap config: %v", err)
+	}
+	if err := os.WriteFile(configPath, configBytes, 0600); err != nil {
+		t.Fatalf("Failed to write bootstrap config: %v", err)
+	}
+
+	result, err := Bootstrap(configPath)
+	if err != nil {
+		t.Fatalf("Failed to bootstrap: %v", err)
+	}
+
+	if result.Opts.AccountResolver == nil {
+		t.Fatal("Expected a resolver to be installed on the returned options")
+	}
+	if len(result.Opts.TrustedOperators) != 1 {
+		t.Fatalf("Expected 1 trusted operator, got: %d", len(result.Opts.TrustedOperators))
+	}
+
+	account, exists := result.Accounts["TENANT_A"]
+	if !exists {
+		t.Fatal("Expected TENANT_A to be provisioned")
+	}
+	if _, exists := result.Users["TENANT_A.alice"]; !exists {
+		t.Fatal("Expected TENANT_A.alice to be provisioned")
+	}
+
+	accountJWT, err := result.Opts.AccountResolver.Fetch(account.PublicKey())
+	if err != nil {
+		t.Fatalf("Expected resolver to have TENANT_A preloaded: %v", err)
+	}
+	if accountJWT == "" {
+		t.Error("Expected a non-empty preloaded account JWT")
+	}
+}
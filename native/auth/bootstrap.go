@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// BootstrapUserConfig describes one user to provision under its account
+// during Bootstrap.
+type BootstrapUserConfig struct {
+	Name             string   `json:"name"`
+	AllowedPublish   []string `json:"allowed_publish"`
+	AllowedSubscribe []string `json:"allowed_subscribe"`
+}
+
+// BootstrapAccountConfig describes one account, and its users, to
+// provision during Bootstrap.
+type BootstrapAccountConfig struct {
+	Name             string                `json:"name"`
+	MaxConnections   int                   `json:"max_connections"`
+	MaxSubscriptions int                   `json:"max_subscriptions"`
+	MaxData          int64                 `json:"max_data"`
+	MaxPayload       int64                 `json:"max_payload"`
+	Users            []BootstrapUserConfig `json:"users"`
+}
+
+// BootstrapResolverConfig is BootstrapConfig's account-resolver section.
+type BootstrapResolverConfig struct {
+	Kind        ResolverKind `json:"kind"` // "mem" or "dir"; defaults to "mem"
+	Dir         string       `json:"dir"`
+	AllowDelete bool         `json:"allow_delete"`
+	TimeoutSecs int          `json:"timeout_secs"`
+}
+
+// BootstrapConfig is the single JSON config file Bootstrap reads to stand
+// up a self-contained multi-tenant deployment: one operator, its account
+// resolver, and every account/user hanging off it.
+type BootstrapConfig struct {
+	OperatorName  string                   `json:"operator_name"`
+	KeystoreDir   string                   `json:"keystore_dir"`
+	SystemAccount string                   `json:"system_account"`
+	Resolver      BootstrapResolverConfig  `json:"resolver"`
+	Accounts      []BootstrapAccountConfig `json:"accounts"`
+}
+
+// BootstrapResult is everything Bootstrap provisioned, ready for the
+// caller to pass Opts straight to server.NewServer.
+type BootstrapResult struct {
+	Opts     *server.Options
+	Operator *OperatorKit
+	Accounts map[string]*Account
+	Users    map[string]*User
+}
+
+// Bootstrap reads configPath and provisions a complete operator/account/
+// user trust chain from it in one call - the one-file setup a multi-tenant
+// embedder needs instead of wiring OperatorKit, Account, User, and
+// BuildResolver together by hand.
+func Bootstrap(configPath string) (*BootstrapResult, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bootstrap config: %w", err)
+	}
+
+	var cfg BootstrapConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse bootstrap config: %w", err)
+	}
+
+	ks, err := NewFileKeystore(cfg.KeystoreDir)
+	if err != nil {
+		return nil, err
+	}
+
+	operator, err := NewOperatorKit(cfg.OperatorName, ks)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.SystemAccount != "" {
+		operator.SetSystemAccount(cfg.SystemAccount)
+	}
+
+	result := &BootstrapResult{
+		Operator: operator,
+		Accounts: make(map[string]*Account, len(cfg.Accounts)),
+		Users:    make(map[string]*User),
+	}
+
+	preload := make(map[string]string, len(cfg.Accounts))
+	for _, acctCfg := range cfg.Accounts {
+		account, err := operator.IssueAccount(acctCfg.Name, AccountLimits{
+			MaxConnections:   acctCfg.MaxConnections,
+			MaxSubscriptions: acctCfg.MaxSubscriptions,
+			MaxData:          acctCfg.MaxData,
+			MaxPayload:       acctCfg.MaxPayload,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to issue account %q: %w", acctCfg.Name, err)
+		}
+		result.Accounts[acctCfg.Name] = account
+
+		accountJWT, err := account.JWT()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode account %q JWT: %w", acctCfg.Name, err)
+		}
+		preload[account.PublicKey()] = accountJWT
+
+		for _, userCfg := range acctCfg.Users {
+			user, err := account.IssueUser(userCfg.Name, UserPermissions{
+				AllowedPublish:   userCfg.AllowedPublish,
+				AllowedSubscribe: userCfg.AllowedSubscribe,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to issue user %q: %w", userCfg.Name, err)
+			}
+			result.Users[acctCfg.Name+"."+userCfg.Name] = user
+		}
+	}
+
+	resolverKind := cfg.Resolver.Kind
+	if resolverKind == "" {
+		resolverKind = ResolverMem
+	}
+	resolver, err := BuildResolver(ResolverConfig{
+		Kind:        resolverKind,
+		Dir:         cfg.Resolver.Dir,
+		AllowDelete: cfg.Resolver.AllowDelete,
+		Timeout:     time.Duration(cfg.Resolver.TimeoutSecs) * time.Second,
+		Preload:     preload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &server.Options{}
+	if err := InstallResolver(opts, resolver, operator); err != nil {
+		return nil, err
+	}
+	result.Opts = opts
+
+	return result, nil
+}
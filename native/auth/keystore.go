@@ -0,0 +1,84 @@
+// Package auth provides a Go-native JWT/NKey provisioning API for
+// embedding a multi-tenant nats-server deployment: minting operator,
+// account, and user identities, persisting their seeds, and wiring a
+// resolver into a *server.Options before boot. The cgo bindings in the
+// parent package expose a similar surface over the C ABI for non-Go
+// callers; this package is for callers that can import Go code directly.
+package auth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Keystore persists and retrieves NKey seeds by name, so OperatorKit,
+// Account, and User identities survive process restarts instead of living
+// only in memory.
+type Keystore interface {
+	Put(name string, seed []byte) error
+	Get(name string) ([]byte, error)
+}
+
+// FileKeystore is a Keystore backed by one file per seed in a directory,
+// written with 0600 permissions since a seed grants full control of its
+// identity.
+type FileKeystore struct {
+	dir string
+}
+
+// NewFileKeystore returns a FileKeystore rooted at dir, creating it (and
+// any missing parents) if it doesn't already exist.
+func NewFileKeystore(dir string) (*FileKeystore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create keystore directory: %w", err)
+	}
+	return &FileKeystore{dir: dir}, nil
+}
+
+func (k *FileKeystore) seedPath(name string) string {
+	return filepath.Join(k.dir, name+".nk")
+}
+
+// Put writes seed to this keystore's directory under name.
+func (k *FileKeystore) Put(name string, seed []byte) error {
+	if err := os.WriteFile(k.seedPath(name), seed, 0600); err != nil {
+		return fmt.Errorf("failed to write seed for %q: %w", name, err)
+	}
+	return nil
+}
+
+// Get reads back the seed previously written for name.
+func (k *FileKeystore) Get(name string) ([]byte, error) {
+	seed, err := os.ReadFile(k.seedPath(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed for %q: %w", name, err)
+	}
+	return seed, nil
+}
+
+// MemKeystore is an in-memory Keystore, useful for tests and for
+// deployments that are fine minting fresh identities on every boot.
+type MemKeystore struct {
+	seeds map[string][]byte
+}
+
+// NewMemKeystore returns an empty MemKeystore.
+func NewMemKeystore() *MemKeystore {
+	return &MemKeystore{seeds: make(map[string][]byte)}
+}
+
+// Put stores seed under name for the lifetime of this MemKeystore.
+func (k *MemKeystore) Put(name string, seed []byte) error {
+	k.seeds[name] = seed
+	return nil
+}
+
+// Get returns the seed previously stored for name.
+func (k *MemKeystore) Get(name string) ([]byte, error) {
+	seed, exists := k.seeds[name]
+	if !exists {
+		return nil, fmt.Errorf("no seed on file for %q", name)
+	}
+	return seed, nil
+}
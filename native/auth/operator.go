@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"fmt"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+)
+
+// OperatorKit wraps an operator identity and mints the accounts that hang
+// off its trust chain, persisting every identity it creates to a Keystore.
+type OperatorKit struct {
+	name   string
+	kp     nkeys.KeyPair
+	pub    string
+	claims *jwt.OperatorClaims
+	ks     Keystore
+}
+
+// NewOperatorKit mints a fresh operator identity named name, persists its
+// seed to ks under that name, and returns the kit ready to issue accounts.
+func NewOperatorKit(name string, ks Keystore) (*OperatorKit, error) {
+	kp, err := nkeys.CreateOperator()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create operator key: %w", err)
+	}
+	return newOperatorKit(name, kp, ks)
+}
+
+// LoadOperatorKit rebuilds an OperatorKit from the seed ks has on file for
+// name, so a restarted process resumes issuing accounts under the same
+// trust chain instead of minting a new, unrelated operator.
+func LoadOperatorKit(name string, ks Keystore) (*OperatorKit, error) {
+	seed, err := ks.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	kp, err := nkeys.FromSeed(seed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operator seed for %q: %w", name, err)
+	}
+	return newOperatorKit(name, kp, ks)
+}
+
+func newOperatorKit(name string, kp nkeys.KeyPair, ks Keystore) (*OperatorKit, error) {
+	pub, err := kp.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive operator public key: %w", err)
+	}
+	seed, err := kp.Seed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive operator seed: %w", err)
+	}
+	if err := ks.Put(name, seed); err != nil {
+		return nil, err
+	}
+
+	claims := jwt.NewOperatorClaims(pub)
+	claims.Name = name
+
+	return &OperatorKit{name: name, kp: kp, pub: pub, claims: claims, ks: ks}, nil
+}
+
+// PublicKey returns the operator's public identity.
+func (o *OperatorKit) PublicKey() string {
+	return o.pub
+}
+
+// JWT encodes and returns the operator's current JWT.
+func (o *OperatorKit) JWT() (string, error) {
+	return o.claims.Encode(o.kp)
+}
+
+// SetSystemAccount designates accountPub as this operator's system
+// account, the account the $SYS.REQ.* administrative subjects run under.
+func (o *OperatorKit) SetSystemAccount(accountPub string) {
+	o.claims.SystemAccount = accountPub
+}
+
+// IssueAccount mints a new account identity signed by this operator, with
+// limits applied, and persists its seed to the operator's keystore under
+// name.
+func (o *OperatorKit) IssueAccount(name string, limits AccountLimits) (*Account, error) {
+	kp, err := nkeys.CreateAccount()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account key: %w", err)
+	}
+	return newAccount(name, kp, o.kp, limits, o.ks)
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// ResolverKind selects which server.AccountResolver BuildResolver
+// constructs.
+type ResolverKind string
+
+const (
+	// ResolverMem resolves accounts from a process-local map seeded with
+	// known account JWTs.
+	ResolverMem ResolverKind = "mem"
+	// ResolverDir resolves accounts from a directory on disk that also
+	// syncs updates pushed over $SYS.REQ.ACCOUNT.*.CLAIMS.UPDATE.
+	ResolverDir ResolverKind = "dir"
+)
+
+// ResolverConfig describes the account resolver BuildResolver should
+// construct.
+type ResolverConfig struct {
+	Kind        ResolverKind
+	Dir         string // required for ResolverDir
+	AllowDelete bool
+	Timeout     time.Duration
+	Preload     map[string]string // account pub -> JWT, for ResolverMem
+}
+
+// BuildResolver constructs the server.AccountResolver described by cfg.
+func BuildResolver(cfg ResolverConfig) (server.AccountResolver, error) {
+	switch cfg.Kind {
+	case ResolverMem:
+		resolver := &server.MemAccResolver{}
+		for pub, accountJWT := range cfg.Preload {
+			resolver.Store(pub, accountJWT)
+		}
+		return resolver, nil
+
+	case ResolverDir:
+		deleteType := server.NoDelete
+		if cfg.AllowDelete {
+			deleteType = server.RenameDeleted
+		}
+		resolver, err := server.NewDirAccResolver(cfg.Dir, 0, cfg.Timeout, deleteType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create directory resolver: %w", err)
+		}
+		return resolver, nil
+
+	default:
+		return nil, fmt.Errorf("unknown resolver kind %q", cfg.Kind)
+	}
+}
+
+// InstallResolver attaches resolver to opts and trusts operator's current
+// JWT, so a server booted with opts can verify and look up every account
+// that operator has issued before any client connects.
+func InstallResolver(opts *server.Options, resolver server.AccountResolver, operator *OperatorKit) error {
+	operatorJWT, err := operator.JWT()
+	if err != nil {
+		return err
+	}
+	claims, err := jwt.DecodeOperatorClaims(operatorJWT)
+	if err != nil {
+		return fmt.Errorf("failed to decode operator JWT: %w", err)
+	}
+
+	opts.TrustedOperators = append(opts.TrustedOperators, claims)
+	opts.AccountResolver = resolver
+	if claims.SystemAccount != "" {
+		opts.SystemAccount = claims.SystemAccount
+	}
+	return nil
+}
+
+// PushClaimsUpdate publishes accountJWT to the resolver a running server
+// was booted with, via $SYS.REQ.ACCOUNT.<pub>.CLAIMS.UPDATE - the subject
+// the NATS system account protocol uses to hot-rotate one account's claims
+// without a restart. This targets a single account; PushAccountJWT in the
+// cgo bindings' jwt_lifecycle.go instead broadcasts to $SYS.REQ.CLAIMS.UPDATE.
+func PushClaimsUpdate(nc *nats.Conn, accountPub string, accountJWT string) (string, error) {
+	subject := fmt.Sprintf("$SYS.REQ.ACCOUNT.%s.CLAIMS.UPDATE", accountPub)
+	reply, err := nc.Request(subject, []byte(accountJWT), 5*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to push claims update: %w", err)
+	}
+	return string(reply.Data), nil
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+)
+
+// UserPermissions is the subset of a user's JWT permissions this package
+// programs directly: subject publish/subscribe allow and deny lists, a
+// subscription cap, and an optional absolute expiry.
+type UserPermissions struct {
+	AllowedPublish   []string
+	DeniedPublish    []string
+	AllowedSubscribe []string
+	DeniedSubscribe  []string
+	MaxSubscriptions int
+	Expiry           time.Time
+}
+
+// User wraps a provisioned user identity and the account key that issued
+// it, so it can re-encode its own JWT on demand.
+type User struct {
+	name     string
+	kp       nkeys.KeyPair
+	pub      string
+	seed     []byte
+	issuerKP nkeys.KeyPair
+	claims   *jwt.UserClaims
+}
+
+func newUser(name string, kp, issuerKP nkeys.KeyPair, issuerPub string, perms UserPermissions, ks Keystore) (*User, error) {
+	pub, err := kp.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive user public key: %w", err)
+	}
+	seed, err := kp.Seed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive user seed: %w", err)
+	}
+	if err := ks.Put(name, seed); err != nil {
+		return nil, err
+	}
+
+	claims := jwt.NewUserClaims(pub)
+	claims.Name = name
+	claims.IssuerAccount = issuerPub
+	claims.Pub.Allow.Add(perms.AllowedPublish...)
+	claims.Pub.Deny.Add(perms.DeniedPublish...)
+	claims.Sub.Allow.Add(perms.AllowedSubscribe...)
+	claims.Sub.Deny.Add(perms.DeniedSubscribe...)
+	if perms.MaxSubscriptions > 0 {
+		claims.Limits.Subs = int64(perms.MaxSubscriptions)
+	}
+	if !perms.Expiry.IsZero() {
+		claims.Expires = perms.Expiry.Unix()
+	}
+
+	return &User{name: name, kp: kp, pub: pub, seed: seed, issuerKP: issuerKP, claims: claims}, nil
+}
+
+// PublicKey returns the user's public identity.
+func (u *User) PublicKey() string {
+	return u.pub
+}
+
+// JWT encodes and returns the user's current JWT, signed by its issuing
+// account (or account signing key).
+func (u *User) JWT() (string, error) {
+	return u.claims.Encode(u.issuerKP)
+}
+
+// Creds returns a ready-to-use .creds file body for this user, suitable
+// for handing straight to a nats.go client via nats.UserCredentials.
+func (u *User) Creds() (string, error) {
+	userJWT, err := u.JWT()
+	if err != nil {
+		return "", err
+	}
+	credsBytes, err := jwt.FormatUserConfig(userJWT, u.seed)
+	if err != nil {
+		return "", fmt.Errorf("failed to format user credentials: %w", err)
+	}
+	return string(credsBytes), nil
+}
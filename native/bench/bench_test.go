@@ -0,0 +1,92 @@
+package bench
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// startTestServer boots an in-process NATS server on an ephemeral port for
+// Run's end-to-end test.
+func startTestServer(t *testing.T) *server.Server {
+	t.Helper()
+	opts := &server.Options{Host: "127.0.0.1", Port: -1}
+	srv, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatalf("Failed to create NATS server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("Server did not become ready in time")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+// Test Percentile/Mean over a small, known set of latencies.
+func TestLatencyHistogram_PercentileAndMean(t *testing.T) {
+	h := NewLatencyHistogram()
+	for _, ms := range []int{10, 20, 30, 40, 50} {
+		h.Record(time.Duration(ms) * time.Millisecond)
+	}
+
+	if got := h.Percentile(100); got != 50*time.Millisecond {
+		t.Errorf("Expected p100 of 50ms, got %s", got)
+	}
+	if got := h.Mean(); got != 30*time.Millisecond {
+		t.Errorf("Expected mean of 30ms, got %s", got)
+	}
+}
+
+// Test Run drives publishers and subscribers end to end against a real
+// embedded server and produces a Benchmark with matching counts.
+func TestRun_PubSubEndToEnd(t *testing.T) {
+	srv := startTestServer(t)
+
+	bm, err := Run(RunConfig{
+		URL:     srv.ClientURL(),
+		Subject: "bench.test",
+		NumPubs: 2,
+		NumSubs: 2,
+		NumMsgs: 50,
+		MsgSize: 64,
+	})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(bm.PubSamples) != 2 {
+		t.Errorf("Expected 2 pub samples, got %d", len(bm.PubSamples))
+	}
+	if len(bm.SubSamples) != 2 {
+		t.Errorf("Expected 2 sub samples, got %d", len(bm.SubSamples))
+	}
+
+	pub := bm.PubStats()
+	if pub.MsgCnt != 100 {
+		t.Errorf("Expected 100 total published messages, got %d", pub.MsgCnt)
+	}
+
+	sub := bm.SubStats()
+	if sub.MsgCnt != 200 {
+		t.Errorf("Expected each of 2 subscribers to see all 100 messages (200 total), got %d", sub.MsgCnt)
+	}
+
+	if bm.Latency.Count() == 0 {
+		t.Error("Expected latency samples to have been recorded")
+	}
+
+	report := bm.Report()
+	if !strings.Contains(report, "Pub stats") || !strings.Contains(report, "Sub stats") {
+		t.Errorf("Expected report to contain pub/sub stats, got: %s", report)
+	}
+}
+
+// Test Run rejects an invalid configuration rather than hanging.
+func TestRun_RejectsInvalidConfig(t *testing.T) {
+	if _, err := Run(RunConfig{URL: "nats://127.0.0.1:1", Subject: "x"}); err == nil {
+		t.Fatal("Expected an error for a config with zero pubs/subs/msgs")
+	}
+}
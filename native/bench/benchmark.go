@@ -0,0 +1,79 @@
+package bench
+
+import (
+	"sync"
+	"time"
+)
+
+// Benchmark aggregates every publisher's and subscriber's Sample from one
+// run, plus the subscriber-side latency distribution, into the figures
+// Report prints. AddPubSample/AddSubSample are safe to call concurrently,
+// since Run (runner.go) adds samples from each client's own goroutine.
+type Benchmark struct {
+	Name    string
+	Latency *LatencyHistogram
+
+	mu         sync.Mutex
+	PubSamples []*Sample
+	SubSamples []*Sample
+}
+
+// NewBenchmark returns an empty Benchmark ready to have samples added to
+// it as publishers/subscribers finish.
+func NewBenchmark(name string) *Benchmark {
+	return &Benchmark{Name: name, Latency: NewLatencyHistogram()}
+}
+
+// AddPubSample records one publisher's completed Sample.
+func (b *Benchmark) AddPubSample(s *Sample) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.PubSamples = append(b.PubSamples, s)
+}
+
+// AddSubSample records one subscriber's completed Sample.
+func (b *Benchmark) AddSubSample(s *Sample) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.SubSamples = append(b.SubSamples, s)
+}
+
+// aggregate combines a slice of samples into one covering their total
+// message/byte counts and the earliest-start/latest-end window across all
+// of them - the usual way per-client samples are rolled up into an
+// aggregate throughput figure.
+func aggregate(samples []*Sample) *Sample {
+	if len(samples) == 0 {
+		return &Sample{}
+	}
+	agg := &Sample{Start: samples[0].Start, End: samples[0].End}
+	for _, s := range samples {
+		agg.MsgCnt += s.MsgCnt
+		agg.MsgBytes += s.MsgBytes
+		if s.Start.Before(agg.Start) {
+			agg.Start = s.Start
+		}
+		if s.End.After(agg.End) {
+			agg.End = s.End
+		}
+	}
+	return agg
+}
+
+// PubStats aggregates every publisher's Sample into one.
+func (b *Benchmark) PubStats() *Sample {
+	return aggregate(b.PubSamples)
+}
+
+// SubStats aggregates every subscriber's Sample into one.
+func (b *Benchmark) SubStats() *Sample {
+	return aggregate(b.SubSamples)
+}
+
+// Duration is the wall-clock span of the whole run, from the first client
+// to start to the last to finish.
+func (b *Benchmark) Duration() time.Duration {
+	all := append(append([]*Sample{}, b.PubSamples...), b.SubSamples...)
+	agg := aggregate(all)
+	return agg.End.Sub(agg.Start)
+}
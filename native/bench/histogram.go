@@ -0,0 +1,72 @@
+package bench
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyHistogram records per-message send-to-receive latencies and
+// reports HdrHistogram-style percentiles over them. It trades
+// HdrHistogram's fixed memory footprint for a plain sorted slice, which is
+// simpler and accurate enough at the message counts a single benchmark
+// run produces.
+type LatencyHistogram struct {
+	mu     sync.Mutex
+	values []time.Duration
+	sorted bool
+}
+
+// NewLatencyHistogram returns an empty histogram ready to Record into.
+func NewLatencyHistogram() *LatencyHistogram {
+	return &LatencyHistogram{}
+}
+
+// Record adds one observed latency.
+func (h *LatencyHistogram) Record(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.values = append(h.values, d)
+	h.sorted = false
+}
+
+// Percentile returns the latency at p (0-100), e.g. Percentile(99) for
+// p99. Returns 0 if no samples were recorded.
+func (h *LatencyHistogram) Percentile(p float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.values) == 0 {
+		return 0
+	}
+	if !h.sorted {
+		sort.Slice(h.values, func(i, j int) bool { return h.values[i] < h.values[j] })
+		h.sorted = true
+	}
+
+	idx := int(p / 100 * float64(len(h.values)))
+	if idx >= len(h.values) {
+		idx = len(h.values) - 1
+	}
+	return h.values[idx]
+}
+
+// Mean returns the arithmetic mean of every recorded latency.
+func (h *LatencyHistogram) Mean() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.values) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, v := range h.values {
+		total += v
+	}
+	return total / time.Duration(len(h.values))
+}
+
+// Count returns the number of recorded latencies.
+func (h *LatencyHistogram) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.values)
+}
@@ -0,0 +1,54 @@
+package bench
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Report formats a normalized summary of the run: aggregate and
+// per-client pub/sub throughput, plus latency percentiles, so two runs
+// (e.g. before/after a broker change) can be compared at a glance.
+func (b *Benchmark) Report() string {
+	var sb strings.Builder
+
+	pub := b.PubStats()
+	sub := b.SubStats()
+
+	fmt.Fprintf(&sb, "Benchmark %q\n", b.Name)
+	fmt.Fprintf(&sb, " Pub stats: %d publishers, %s msgs/sec, %s\n",
+		len(b.PubSamples), formatRate(pub.Throughput()), formatBytesRate(pub.Rate()))
+	fmt.Fprintf(&sb, " Sub stats: %d subscribers, %s msgs/sec, %s\n",
+		len(b.SubSamples), formatRate(sub.Throughput()), formatBytesRate(sub.Rate()))
+
+	if b.Latency.Count() > 0 {
+		fmt.Fprintf(&sb, " Latency: mean %s, p50 %s, p90 %s, p99 %s (n=%d)\n",
+			b.Latency.Mean(), b.Latency.Percentile(50), b.Latency.Percentile(90),
+			b.Latency.Percentile(99), b.Latency.Count())
+	}
+
+	for i, s := range b.PubSamples {
+		fmt.Fprintf(&sb, "  [pub %d] %s msgs/sec, %s\n", i+1, formatRate(s.Throughput()), formatBytesRate(s.Rate()))
+	}
+	for i, s := range b.SubSamples {
+		fmt.Fprintf(&sb, "  [sub %d] %s msgs/sec, %s\n", i+1, formatRate(s.Throughput()), formatBytesRate(s.Rate()))
+	}
+
+	return sb.String()
+}
+
+func formatRate(msgsPerSec float64) string {
+	return fmt.Sprintf("%.0f", msgsPerSec)
+}
+
+func formatBytesRate(bytesPerSec float64) string {
+	const unit = 1024
+	if bytesPerSec < unit {
+		return fmt.Sprintf("%.0f B/sec", bytesPerSec)
+	}
+	div, exp := float64(unit), 0
+	for n := bytesPerSec / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %ciB/sec", bytesPerSec/div, "KMGTPE"[exp])
+}
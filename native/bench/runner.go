@@ -0,0 +1,193 @@
+package bench
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// RunConfig parameterizes one Run: how many publishers/subscribers to
+// spin up, what to publish, and how to connect - against either the
+// module's own embedded server (pass its ClientURL()) or any external NATS
+// URL.
+type RunConfig struct {
+	URL     string
+	Subject string
+	NumPubs int
+	NumSubs int
+	NumMsgs int // per publisher
+	MsgSize int
+
+	Creds     string // path to a .creds file, if JWT auth is required
+	TLSCert   string
+	TLSKey    string
+	TLSCACert string
+}
+
+// connectOptions builds the nats.Option set every publisher/subscriber
+// connection in a Run shares, mirroring the file-path-based TLS/creds
+// configuration the rest of this module already uses (see
+// http_gateway.go's TLSConfigOpts-based setup).
+func (cfg RunConfig) connectOptions() ([]nats.Option, error) {
+	var opts []nats.Option
+	if cfg.Creds != "" {
+		opts = append(opts, nats.UserCredentials(cfg.Creds))
+	}
+	if cfg.TLSCACert != "" {
+		opts = append(opts, nats.RootCAs(cfg.TLSCACert))
+	}
+	if cfg.TLSCert != "" && cfg.TLSKey != "" {
+		opts = append(opts, nats.ClientCert(cfg.TLSCert, cfg.TLSKey))
+	}
+	return opts, nil
+}
+
+// Run connects cfg.NumSubs subscribers and cfg.NumPubs publishers to
+// cfg.URL, waits for every subscriber to receive every published message,
+// and returns the resulting Benchmark. Subscribers are subscribed before
+// any publisher starts, so no published message is missed.
+func Run(cfg RunConfig) (*Benchmark, error) {
+	if cfg.NumPubs <= 0 || cfg.NumSubs <= 0 || cfg.NumMsgs <= 0 {
+		return nil, fmt.Errorf("NumPubs, NumSubs, and NumMsgs must all be positive")
+	}
+
+	opts, err := cfg.connectOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	bm := NewBenchmark(cfg.Subject)
+	expected := uint64(cfg.NumPubs * cfg.NumMsgs)
+
+	var subsReady sync.WaitGroup
+	var subsDone sync.WaitGroup
+	subsReady.Add(cfg.NumSubs)
+	subsDone.Add(cfg.NumSubs)
+
+	subErrs := make(chan error, cfg.NumSubs)
+	for i := 0; i < cfg.NumSubs; i++ {
+		go runSubscriber(cfg, opts, expected, bm, &subsReady, &subsDone, subErrs)
+	}
+	subsReady.Wait()
+
+	var pubsDone sync.WaitGroup
+	pubsDone.Add(cfg.NumPubs)
+	pubErrs := make(chan error, cfg.NumPubs)
+	for i := 0; i < cfg.NumPubs; i++ {
+		go runPublisher(cfg, opts, bm, &pubsDone, pubErrs)
+	}
+	pubsDone.Wait()
+	close(pubErrs)
+	for err := range pubErrs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	subsDone.Wait()
+	close(subErrs)
+	for err := range subErrs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bm, nil
+}
+
+// runPublisher connects once and publishes cfg.NumMsgs messages of
+// cfg.MsgSize bytes to cfg.Subject, each payload prefixed with its send
+// time so subscribers can compute end-to-end latency.
+func runPublisher(cfg RunConfig, opts []nats.Option, bm *Benchmark, wg *sync.WaitGroup, errs chan<- error) {
+	defer wg.Done()
+
+	nc, err := nats.Connect(cfg.URL, opts...)
+	if err != nil {
+		errs <- fmt.Errorf("publisher failed to connect: %w", err)
+		return
+	}
+	defer nc.Close()
+
+	payload := make([]byte, cfg.MsgSize)
+	sample := &Sample{Start: time.Now()}
+
+	for i := 0; i < cfg.NumMsgs; i++ {
+		if len(payload) >= 8 {
+			binary.BigEndian.PutUint64(payload[:8], uint64(time.Now().UnixNano()))
+		}
+		if err := nc.Publish(cfg.Subject, payload); err != nil {
+			errs <- fmt.Errorf("publisher failed to publish: %w", err)
+			return
+		}
+		sample.MsgCnt++
+		sample.MsgBytes += uint64(len(payload))
+	}
+	if err := nc.Flush(); err != nil {
+		errs <- fmt.Errorf("publisher failed to flush: %w", err)
+		return
+	}
+	sample.End = time.Now()
+
+	bm.AddPubSample(sample)
+	errs <- nil
+}
+
+// runSubscriber connects once, signals readyWg once subscribed, and
+// records a Sample covering every message received up to expected,
+// recording each message's latency against bm.Latency along the way.
+func runSubscriber(cfg RunConfig, opts []nats.Option, expected uint64, bm *Benchmark, readyWg, doneWg *sync.WaitGroup, errs chan<- error) {
+	defer doneWg.Done()
+
+	nc, err := nats.Connect(cfg.URL, opts...)
+	if err != nil {
+		readyWg.Done()
+		errs <- fmt.Errorf("subscriber failed to connect: %w", err)
+		return
+	}
+	defer nc.Close()
+
+	sample := &Sample{}
+	finished := make(chan struct{})
+
+	sub, err := nc.Subscribe(cfg.Subject, func(msg *nats.Msg) {
+		if sample.MsgCnt == 0 {
+			sample.Start = time.Now()
+		}
+		sample.MsgCnt++
+		sample.MsgBytes += uint64(len(msg.Data))
+		if len(msg.Data) >= 8 {
+			sentNano := int64(binary.BigEndian.Uint64(msg.Data[:8]))
+			bm.Latency.Record(time.Since(time.Unix(0, sentNano)))
+		}
+		if sample.MsgCnt >= expected {
+			sample.End = time.Now()
+			close(finished)
+		}
+	})
+	if err != nil {
+		readyWg.Done()
+		errs <- fmt.Errorf("subscriber failed to subscribe: %w", err)
+		return
+	}
+	defer sub.Unsubscribe()
+
+	if err := nc.Flush(); err != nil {
+		readyWg.Done()
+		errs <- fmt.Errorf("subscriber failed to flush subscribe: %w", err)
+		return
+	}
+	readyWg.Done()
+
+	select {
+	case <-finished:
+	case <-time.After(30 * time.Second):
+		errs <- fmt.Errorf("subscriber timed out waiting for %d messages, got %d", expected, sample.MsgCnt)
+		return
+	}
+
+	bm.AddSubSample(sample)
+	errs <- nil
+}
@@ -0,0 +1,44 @@
+// Package bench provides a reusable load-generation and benchmarking
+// harness for this module's embedded NATS server, porting the ergonomics
+// of nats.go's bench package (Sampler/Benchmark) to a library other Go
+// code can drive directly, plus the cmd/mbn-bench binary that wraps it
+// with flags. Like native/auth, native/codec, and native/spool, it lives
+// outside the cgo package because it's a plain Go API, not something
+// meant to cross the C ABI.
+package bench
+
+import "time"
+
+// Sample is one publisher or subscriber's contribution to a Benchmark
+// run: how many messages and bytes it moved, and over what wall-clock
+// window.
+type Sample struct {
+	MsgCnt   uint64
+	MsgBytes uint64
+	Start    time.Time
+	End      time.Time
+}
+
+// Duration is the wall-clock time the sample's client spent publishing or
+// subscribing.
+func (s *Sample) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Throughput is the sample's messages per second.
+func (s *Sample) Throughput() float64 {
+	d := s.Duration().Seconds()
+	if d <= 0 {
+		return 0
+	}
+	return float64(s.MsgCnt) / d
+}
+
+// Rate is the sample's bytes per second.
+func (s *Sample) Rate() float64 {
+	d := s.Duration().Seconds()
+	if d <= 0 {
+		return 0
+	}
+	return float64(s.MsgBytes) / d
+}
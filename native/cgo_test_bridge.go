@@ -0,0 +1,51 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import "unsafe"
+
+// cgo is unavailable in any file whose name ends in _test.go - the Go
+// toolchain ignores "C" preamble processing there entirely - so every
+// _test.go file in this package that needs to marshal a Go value into one
+// of this package's //export C-string/C-int parameters, or unmarshal one
+// of their *C.char results, goes through the plain-Go-signature helpers
+// below instead of importing "C" itself.
+
+// cString converts s to a *C.char the caller must free with cFree.
+func cString(s string) *C.char {
+	return C.CString(s)
+}
+
+// cFree frees a *C.char previously obtained from cString or returned by one
+// of this package's //export functions.
+func cFree(cs *C.char) {
+	C.free(unsafe.Pointer(cs))
+}
+
+// goString converts cs to a Go string without freeing it.
+func goString(cs *C.char) string {
+	return C.GoString(cs)
+}
+
+// goStringFree converts cs to a Go string and frees it - the pattern
+// nearly every //export function's *C.char result needs at its only use
+// site in a test.
+func goStringFree(cs *C.char) string {
+	defer C.free(unsafe.Pointer(cs))
+	return C.GoString(cs)
+}
+
+// cInt converts a Go int to the C.int type several //export functions take
+// (e.g. WaitForReadyStateFor's timeoutSeconds, SubscribeMonitoring's
+// intervalMs).
+func cInt(n int) C.int {
+	return C.int(n)
+}
+
+// cULonglong converts a Go uint64 to the C.ulonglong type DisconnectClientByID
+// and GetClientInfo take for a client's connection ID.
+func cULonglong(n uint64) C.ulonglong {
+	return C.ulonglong(n)
+}
@@ -0,0 +1,117 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// GetClusterName returns the cluster name the current server is
+// configured with.
+//
+//export GetClusterName
+func GetClusterName() *C.char {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, exists := natsServers[currentPort]
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	return C.CString(srv.ClusterName())
+}
+
+// GetNumRoutes returns the number of active routes (peer connections) the
+// current server has.
+//
+//export GetNumRoutes
+func GetNumRoutes() *C.char {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, exists := natsServers[currentPort]
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	return C.CString(strconv.Itoa(srv.NumRoutes()))
+}
+
+// ClusterPeer summarizes one route's remote peer for GetClusterPeers - a
+// leaner shape than the full Routez payload GetRoutez already exposes.
+type ClusterPeer struct {
+	RemoteID string `json:"remote_id"`
+	IP       string `json:"ip"`
+	Port     int    `json:"port"`
+}
+
+// GetClusterPeers returns the set of peers the current server has routes
+// to, as JSON.
+//
+//export GetClusterPeers
+func GetClusterPeers() *C.char {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, exists := natsServers[currentPort]
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	routez, err := srv.Routez(nil)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to get route info: %v", err))
+	}
+
+	peers := make([]ClusterPeer, 0, len(routez.Routes))
+	for _, route := range routez.Routes {
+		peers = append(peers, ClusterPeer{
+			RemoteID: route.RemoteID,
+			IP:       route.IP,
+			Port:     route.Port,
+		})
+	}
+
+	jsonBytes, err := json.Marshal(peers)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal cluster peers: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// AddRoute adds a peer route URL to the current server and reloads options
+// so the new route is dialed without a restart, mirroring how
+// AddLeafRemote patches LeafNode.Remotes in remotes.go.
+//
+//export AddRoute
+func AddRoute(routeURL *C.char) *C.char {
+	if routeURL == nil {
+		return C.CString("ERROR: route URL cannot be null")
+	}
+
+	parsedURL, err := url.Parse(C.GoString(routeURL))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Invalid route URL: %v", err))
+	}
+
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, opts, err := currentOptsLocked()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	opts.Routes = append(opts.Routes, parsedURL)
+	if err := srv.ReloadOptions(opts); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to reload options: %v", err))
+	}
+
+	return C.CString("OK")
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// waitForNumRoutes polls GetNumRoutes for the currently-selected server
+// until it reports at least want routes, analogous to the WaitForResult
+// membership-check helpers used against Consul-style registries.
+func waitForNumRoutes(t *testing.T, want int, timeout time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		response := goStringFree(GetNumRoutes())
+		if response == fmt.Sprintf("%d", want) {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d route(s)", want)
+}
+
+// Test that two in-process servers routed together via cluster config
+// discover each other through GetNumRoutes/GetClusterPeers.
+func TestClusterRouting_TwoServersDiscoverPeer(t *testing.T) {
+	portA, clusterPortA := 14360, 14361
+	portB, clusterPortB := 14362, 14363
+
+	configA := cString(fmt.Sprintf(
+		`{"host":"127.0.0.1","port":%d,"cluster":{"name":"test-cluster","host":"127.0.0.1","port":%d}}`,
+		portA, clusterPortA))
+	defer cFree(configA)
+
+	handleA := goStringFree(StartServerInstance(configA))
+	if isErrorResponse(handleA) {
+		t.Fatalf("Expected handle starting server A, got: %s", handleA)
+	}
+
+	configB := cString(fmt.Sprintf(
+		`{"host":"127.0.0.1","port":%d,"cluster":{"name":"test-cluster","host":"127.0.0.1","port":%d,"routes":["nats-route://127.0.0.1:%d"]}}`,
+		portB, clusterPortB, clusterPortA))
+	defer cFree(configB)
+
+	handleB := goStringFree(StartServerInstance(configB))
+	if isErrorResponse(handleB) {
+		t.Fatalf("Expected handle starting server B, got: %s", handleB)
+	}
+
+	handleAC := cString(handleA)
+	defer cFree(handleAC)
+	handleBC := cString(handleB)
+	defer cFree(handleBC)
+
+	defer func() {
+		cFree(ShutdownInstance(handleAC))
+		cFree(ShutdownInstance(handleBC))
+	}()
+
+	SetCurrentPort(cInt(portA))
+	waitForNumRoutes(t, 1, 5*time.Second)
+
+	peersResponse := goStringFree(GetClusterPeers())
+	if isErrorResponse(peersResponse) {
+		t.Fatalf("Expected peer list from server A, got: %s", peersResponse)
+	}
+	if peersResponse == "[]" {
+		t.Error("Expected server A to report a peer, got an empty list")
+	}
+
+	nameResponse := goStringFree(GetClusterName())
+	if nameResponse != "test-cluster" {
+		t.Errorf("Expected cluster name 'test-cluster', got: %s", nameResponse)
+	}
+
+	SetCurrentPort(cInt(portB))
+	waitForNumRoutes(t, 1, 5*time.Second)
+
+	peersResult = GetClusterPeers()
+	peersResponse = goString(peersResult)
+	cFree(peersResult)
+	if isErrorResponse(peersResponse) {
+		t.Fatalf("Expected peer list from server B, got: %s", peersResponse)
+	}
+	if peersResponse == "[]" {
+		t.Error("Expected server B to report a peer, got an empty list")
+	}
+}
+
+// Test that AddRoute connects a standalone server to a peer at runtime.
+func TestAddRoute_ConnectsRunningServers(t *testing.T) {
+	portA, clusterPortA := 14364, 14365
+	portB, clusterPortB := 14366, 14367
+
+	configA := cString(fmt.Sprintf(
+		`{"host":"127.0.0.1","port":%d,"cluster":{"name":"test-cluster","host":"127.0.0.1","port":%d}}`,
+		portA, clusterPortA))
+	defer cFree(configA)
+	handleA := goString(StartServerInstance(configA))
+
+	configB := cString(fmt.Sprintf(
+		`{"host":"127.0.0.1","port":%d,"cluster":{"name":"test-cluster","host":"127.0.0.1","port":%d}}`,
+		portB, clusterPortB))
+	defer cFree(configB)
+	handleB := goString(StartServerInstance(configB))
+
+	handleAC := cString(handleA)
+	defer cFree(handleAC)
+	handleBC := cString(handleB)
+	defer cFree(handleBC)
+	defer func() {
+		cFree(ShutdownInstance(handleAC))
+		cFree(ShutdownInstance(handleBC))
+	}()
+
+	SetCurrentPort(cInt(portB))
+
+	routeURL := cString(fmt.Sprintf("nats-route://127.0.0.1:%d", clusterPortA))
+	defer cFree(routeURL)
+
+	addResponse := goStringFree(AddRoute(routeURL))
+	if isErrorResponse(addResponse) {
+		t.Fatalf("Expected success adding route, got: %s", addResponse)
+	}
+
+	waitForNumRoutes(t, 1, 5*time.Second)
+}
@@ -0,0 +1,39 @@
+// Command mbn-bench wraps native/bench with flags for message size,
+// subject fan-out, JWT credentials, and TLS, so broker changes and
+// configurations can be regression-tested and compared reproducibly from
+// the command line.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/four-robots/messagebroker.net/native/bench"
+)
+
+func main() {
+	var cfg bench.RunConfig
+
+	flag.StringVar(&cfg.URL, "url", nats.DefaultURL, "NATS server URL to benchmark against")
+	flag.StringVar(&cfg.Subject, "subject", "mbn-bench", "subject to publish/subscribe on; use a wildcard (e.g. \"mbn-bench.*\") to fan out across multiple subjects")
+	flag.IntVar(&cfg.NumPubs, "pubs", 1, "number of concurrent publishers")
+	flag.IntVar(&cfg.NumSubs, "subs", 1, "number of concurrent subscribers")
+	flag.IntVar(&cfg.NumMsgs, "msgs", 100000, "number of messages published per publisher")
+	flag.IntVar(&cfg.MsgSize, "size", 128, "message payload size in bytes")
+	flag.StringVar(&cfg.Creds, "creds", "", "path to a .creds file for JWT authentication")
+	flag.StringVar(&cfg.TLSCert, "tls-cert", "", "path to a client TLS certificate")
+	flag.StringVar(&cfg.TLSKey, "tls-key", "", "path to the client TLS certificate's private key")
+	flag.StringVar(&cfg.TLSCACert, "tls-ca", "", "path to a CA certificate to verify the server against")
+	flag.Parse()
+
+	bm, err := bench.Run(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mbn-bench: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(bm.Report())
+}
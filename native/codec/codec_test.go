@@ -0,0 +1,143 @@
+package codec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// startTestServer boots an in-process NATS server on an ephemeral port for
+// PublishMsg/SubscribeTyped's end-to-end test.
+func startTestServer(t *testing.T) *server.Server {
+	t.Helper()
+	opts := &server.Options{Host: "127.0.0.1", Port: -1}
+	srv, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatalf("Failed to create NATS server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("Server did not become ready in time")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+type widget struct {
+	Name  string
+	Count int
+}
+
+// Test the default (JSON) encoder roundtrips a struct.
+func TestJSONEncoder_RoundTrip(t *testing.T) {
+	enc, exists := Get("default")
+	if !exists {
+		t.Fatal("Expected 'default' encoder to be registered")
+	}
+
+	data, err := enc.Encode("widgets.created", widget{Name: "sprocket", Count: 3})
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+
+	var decoded widget
+	if err := enc.Decode("widgets.created", data, &decoded); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if decoded != (widget{Name: "sprocket", Count: 3}) {
+		t.Errorf("Expected decoded widget to match original, got: %+v", decoded)
+	}
+}
+
+// Test the gob encoder roundtrips a struct.
+func TestGobEncoder_RoundTrip(t *testing.T) {
+	enc, exists := Get("gob")
+	if !exists {
+		t.Fatal("Expected 'gob' encoder to be registered")
+	}
+
+	data, err := enc.Encode("widgets.created", widget{Name: "cog", Count: 7})
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+
+	var decoded widget
+	if err := enc.Decode("widgets.created", data, &decoded); err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if decoded != (widget{Name: "cog", Count: 7}) {
+		t.Errorf("Expected decoded widget to match original, got: %+v", decoded)
+	}
+}
+
+// Test the protobuf encoder rejects a value that doesn't implement
+// Marshal()/Unmarshal().
+func TestProtobufEncoder_RejectsNonProtoValue(t *testing.T) {
+	enc, exists := Get("protobuf")
+	if !exists {
+		t.Fatal("Expected 'protobuf' encoder to be registered")
+	}
+
+	if _, err := enc.Encode("widgets.created", widget{Name: "gear"}); err == nil {
+		t.Fatal("Expected an error encoding a non-proto value")
+	}
+}
+
+// Test Register overrides an existing name and Get reflects the change.
+func TestRegister_OverridesExisting(t *testing.T) {
+	original, _ := Get("default")
+	defer Register("default", original)
+
+	Register("default", gobEncoder{})
+	enc, _ := Get("default")
+	if _, ok := enc.(gobEncoder); !ok {
+		t.Errorf("Expected 'default' to be overridden with gobEncoder, got: %T", enc)
+	}
+}
+
+// Test WithEncoder/resolveOptions picks the requested encoder, falling
+// back to "default" when unset.
+func TestResolveOptions_DefaultsAndOverrides(t *testing.T) {
+	if cfg := resolveOptions(nil); cfg.encoderName != "default" {
+		t.Errorf("Expected default encoder name 'default', got: %q", cfg.encoderName)
+	}
+	if cfg := resolveOptions([]Option{WithEncoder("gob")}); cfg.encoderName != "gob" {
+		t.Errorf("Expected encoder name 'gob', got: %q", cfg.encoderName)
+	}
+}
+
+// Test PublishMsg/SubscribeTyped deliver a typed value end to end over a
+// real connection, using the gob encoder to confirm WithEncoder is honored.
+func TestPublishMsg_SubscribeTyped_EndToEnd(t *testing.T) {
+	srv := startTestServer(t)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer nc.Close()
+
+	received := make(chan *widget, 1)
+	sub, err := SubscribeTyped(nc, "widgets.created", func(subject string, v *widget) {
+		received <- v
+	}, WithEncoder("gob"))
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	if err := PublishMsg(nc, "widgets.created", widget{Name: "sprocket", Count: 3}, WithEncoder("gob")); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+
+	select {
+	case v := <-received:
+		if *v != (widget{Name: "sprocket", Count: 3}) {
+			t.Errorf("Expected received widget to match published value, got: %+v", *v)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for message delivery")
+	}
+}
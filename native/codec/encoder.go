@@ -0,0 +1,57 @@
+// Package codec provides pluggable message encoders and a small
+// generics-based publish/subscribe surface on top of *nats.Conn. It lives
+// outside the cgo-exported package (see ../jetstream_admin.go for the
+// byte-oriented JSPublish equivalent) because Go generics - SubscribeTyped
+// below - can't be declared with a //export comment; this package is for
+// Go callers that can import it directly rather than the .NET-facing C
+// ABI.
+package codec
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Encoder converts a Go value to and from the wire bytes published on
+// subject, following the same shape as nats.go's own Encoder interface so
+// existing encoder implementations are a drop-in fit.
+type Encoder interface {
+	Encode(subject string, v any) ([]byte, error)
+	Decode(subject string, data []byte, vPtr any) error
+}
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[string]Encoder{}
+)
+
+// Register adds enc to the registry under name, so PublishMsg/SubscribeTyped
+// callers can select it via WithEncoder(name). Registering under an
+// existing name replaces it.
+func Register(name string, enc Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	encoders[name] = enc
+}
+
+// Get returns the encoder registered under name, if any.
+func Get(name string) (Encoder, bool) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	enc, exists := encoders[name]
+	return enc, exists
+}
+
+func init() {
+	Register("default", jsonEncoder{})
+	Register("gob", gobEncoder{})
+	Register("protobuf", protobufEncoder{})
+}
+
+func mustGet(name string) (Encoder, error) {
+	enc, exists := Get(name)
+	if !exists {
+		return nil, fmt.Errorf("unknown encoder %q", name)
+	}
+	return enc, nil
+}
@@ -0,0 +1,27 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+)
+
+// gobEncoder is the "gob" encoder, matching nats.go/encoders/builtin's gob
+// encoder for Go-to-Go messaging that wants a more compact wire format
+// than JSON.
+type gobEncoder struct{}
+
+func (gobEncoder) Encode(_ string, v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to gob-encode value: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobEncoder) Decode(_ string, data []byte, vPtr any) error {
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(vPtr); err != nil {
+		return fmt.Errorf("failed to gob-decode value: %w", err)
+	}
+	return nil
+}
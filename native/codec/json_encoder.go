@@ -0,0 +1,16 @@
+package codec
+
+import "encoding/json"
+
+// jsonEncoder is the "default" encoder: plain encoding/json, matching
+// nats.go/encoders/builtin's default encoder so existing JSON payloads
+// need no conversion to use PublishMsg/SubscribeTyped.
+type jsonEncoder struct{}
+
+func (jsonEncoder) Encode(_ string, v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonEncoder) Decode(_ string, data []byte, vPtr any) error {
+	return json.Unmarshal(data, vPtr)
+}
@@ -0,0 +1,26 @@
+package codec
+
+// options holds PublishMsg/SubscribeTyped's per-call settings.
+type options struct {
+	encoderName string
+}
+
+// Option configures a single PublishMsg or SubscribeTyped call.
+type Option func(*options)
+
+// WithEncoder selects the encoder PublishMsg/SubscribeTyped uses by its
+// registered name (see Register), overriding the "default" (JSON)
+// encoder.
+func WithEncoder(name string) Option {
+	return func(o *options) {
+		o.encoderName = name
+	}
+}
+
+func resolveOptions(opts []Option) options {
+	cfg := options{encoderName: "default"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
@@ -0,0 +1,40 @@
+package codec
+
+import "fmt"
+
+// protoMarshaler and protoUnmarshaler are the minimal shape generated
+// protobuf types need for the "protobuf" encoder below. Note: this module
+// has no existing dependency on a protobuf runtime (neither
+// google.golang.org/protobuf nor github.com/golang/protobuf appear in
+// go.mod), so rather than add one just for this encoder, protobufEncoder
+// depends on the narrow Marshal/Unmarshal method pair that generated
+// protobuf (and gogo/protobuf) message types already implement - any
+// generated type works here without this package needing to know which
+// protobuf runtime produced it.
+type protoMarshaler interface {
+	Marshal() ([]byte, error)
+}
+
+type protoUnmarshaler interface {
+	Unmarshal([]byte) error
+}
+
+// protobufEncoder is the "protobuf" encoder, matching
+// nats.go/encoders/protobuf's role for typed proto.Message payloads.
+type protobufEncoder struct{}
+
+func (protobufEncoder) Encode(_ string, v any) ([]byte, error) {
+	m, ok := v.(protoMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("value of type %T does not implement Marshal() ([]byte, error)", v)
+	}
+	return m.Marshal()
+}
+
+func (protobufEncoder) Decode(_ string, data []byte, vPtr any) error {
+	m, ok := vPtr.(protoUnmarshaler)
+	if !ok {
+		return fmt.Errorf("value of type %T does not implement Unmarshal([]byte) error", vPtr)
+	}
+	return m.Unmarshal(data)
+}
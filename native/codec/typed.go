@@ -0,0 +1,46 @@
+package codec
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// PublishMsg encodes v with the selected encoder (WithEncoder; "default",
+// i.e. JSON, if unset) and publishes the result to subject on nc, so
+// callers exchanging typed Go values don't hand-marshal on every call
+// site.
+func PublishMsg(nc *nats.Conn, subject string, v any, opts ...Option) error {
+	cfg := resolveOptions(opts)
+	enc, err := mustGet(cfg.encoderName)
+	if err != nil {
+		return err
+	}
+
+	data, err := enc.Encode(subject, v)
+	if err != nil {
+		return fmt.Errorf("failed to encode message for %q: %w", subject, err)
+	}
+	return nc.Publish(subject, data)
+}
+
+// SubscribeTyped subscribes to subject on nc, decoding each message into a
+// fresh *T with the selected encoder before invoking cb. A message that
+// fails to decode is dropped rather than delivered with a zero-value T,
+// since the type mismatch usually means cb would misinterpret it anyway.
+func SubscribeTyped[T any](nc *nats.Conn, subject string, cb func(subject string, v *T), opts ...Option) (*nats.Subscription, error) {
+	cfg := resolveOptions(opts)
+	enc, err := mustGet(cfg.encoderName)
+	if err != nil {
+		return nil, err
+	}
+
+	return nc.Subscribe(subject, func(msg *nats.Msg) {
+		var v T
+		if err := enc.Decode(msg.Subject, msg.Data, &v); err != nil {
+			// Drop messages that fail to decode (logged elsewhere if needed).
+			return
+		}
+		cb(msg.Subject, &v)
+	})
+}
@@ -0,0 +1,200 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// optsSnapshotLocked builds the flat, JSON-friendly view of srv's current
+// options that GetOpts returns and ReloadConfigWithDiff/ReloadConfigInline
+// diff across a reload - server.Options can't be marshaled directly because
+// of its unexported fields. Caller must hold serverMu.
+func optsSnapshotLocked(srv *server.Server) map[string]interface{} {
+	opts := srv.GetOpts()
+	if opts == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"host":                 opts.Host,
+		"port":                 opts.Port,
+		"max_payload":          opts.MaxPayload,
+		"max_control_line":     opts.MaxControlLine,
+		"max_pings_out":        opts.MaxPingsOut,
+		"debug":                opts.Debug,
+		"trace":                opts.Trace,
+		"logtime":              opts.Logtime,
+		"log_file":             opts.LogFile,
+		"log_size_limit":       opts.LogSizeLimit,
+		"jetstream":            opts.JetStream,
+		"jetstream_max_memory": opts.JetStreamMaxMemory,
+		"jetstream_max_store":  opts.JetStreamMaxStore,
+		"jetstream_domain":     opts.JetStreamDomain,
+		"jetstream_unique_tag": opts.JetStreamUniqueTag,
+		"store_dir":            opts.StoreDir,
+		"http_host":            opts.HTTPHost,
+		"http_port":            opts.HTTPPort,
+		"https_port":           opts.HTTPSPort,
+		"cluster_name":         opts.Cluster.Name,
+		"cluster_port":         opts.Cluster.Port,
+		"leaf_node_port":       opts.LeafNode.Port,
+	}
+}
+
+// optionChange is one field's before/after value in a configReloadReport.
+type optionChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// configReloadReport is ReloadConfigWithDiff/ReloadConfigInline's response
+// shape: every field GetOpts exposes that changed, was added, or was
+// removed by the reload, plus any error ReloadOptions returned.
+type configReloadReport struct {
+	Changed      map[string]optionChange `json:"changed"`
+	Added        map[string]interface{}  `json:"added"`
+	Removed      map[string]interface{}  `json:"removed"`
+	ReloadErrors []string                `json:"reload_errors"`
+}
+
+func newConfigReloadReport() configReloadReport {
+	return configReloadReport{
+		Changed: map[string]optionChange{},
+		Added:   map[string]interface{}{},
+		Removed: map[string]interface{}{},
+	}
+}
+
+// diffOptsSnapshots compares two optsSnapshotLocked results field by field,
+// the same top-level-key comparison diffSnapshots in monitor_stream.go uses
+// for monitoring deltas.
+func diffOptsSnapshots(before, after map[string]interface{}) configReloadReport {
+	report := newConfigReloadReport()
+
+	for key, afterVal := range after {
+		beforeVal, existed := before[key]
+		if !existed {
+			report.Added[key] = afterVal
+			continue
+		}
+		if !reflect.DeepEqual(beforeVal, afterVal) {
+			report.Changed[key] = optionChange{Old: beforeVal, New: afterVal}
+		}
+	}
+	for key, beforeVal := range before {
+		if _, exists := after[key]; !exists {
+			report.Removed[key] = beforeVal
+		}
+	}
+
+	return report
+}
+
+func marshalConfigReloadReport(report configReloadReport) *C.char {
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal config reload report: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// ReloadConfigWithDiff re-reads the config file at configPath and applies it
+// via srv.ReloadOptions, then reports exactly which GetOpts fields changed,
+// were added, or were removed - so an operator can confirm a reload did (or
+// didn't) do what they expected instead of diffing GetOpts snapshots by
+// hand. A ReloadOptions failure is reported in reload_errors rather than as
+// an ERROR: response, since the pre-reload options are still in effect and
+// the caller may want the (empty) diff alongside the failure reason.
+//
+//export ReloadConfigWithDiff
+func ReloadConfigWithDiff(configPath *C.char) *C.char {
+	if configPath == nil {
+		return C.CString("ERROR: config path cannot be null")
+	}
+
+	opts, err := server.ProcessConfigFile(C.GoString(configPath))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to process config file: %v", err))
+	}
+
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, exists := natsServers[currentPort]
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	before := optsSnapshotLocked(srv)
+
+	if err := srv.ReloadOptions(opts); err != nil {
+		report := newConfigReloadReport()
+		report.ReloadErrors = append(report.ReloadErrors, err.Error())
+		return marshalConfigReloadReport(report)
+	}
+	natsServerOpts[currentPort] = opts
+
+	after := optsSnapshotLocked(srv)
+	return marshalConfigReloadReport(diffOptsSnapshots(before, after))
+}
+
+// ReloadConfigInline is ReloadConfigWithDiff's counterpart for callers that
+// already hold their configuration as a ServerConfig JSON blob (the same
+// shape UpdateAndReloadConfig accepts) rather than a file on disk.
+//
+//export ReloadConfigInline
+func ReloadConfigInline(jsonOpts *C.char) *C.char {
+	if jsonOpts == nil {
+		return C.CString("ERROR: inline options cannot be null")
+	}
+
+	var config ServerConfig
+	if err := json.Unmarshal([]byte(C.GoString(jsonOpts)), &config); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse inline configuration: %v", err))
+	}
+	opts := convertToNatsOptions(&config)
+
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, exists := natsServers[currentPort]
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	before := optsSnapshotLocked(srv)
+
+	if err := srv.ReloadOptions(opts); err != nil {
+		report := newConfigReloadReport()
+		report.ReloadErrors = append(report.ReloadErrors, err.Error())
+		return marshalConfigReloadReport(report)
+	}
+	natsServerOpts[currentPort] = opts
+
+	after := optsSnapshotLocked(srv)
+	return marshalConfigReloadReport(diffOptsSnapshots(before, after))
+}
+
+// ValidateConfig parses configPath via server.ProcessConfigFile without
+// applying it to any running server, so an operator can dry-run changes to
+// JetStream limits, cluster routes, or leaf-node config - catching a syntax
+// or validation error before committing to ReloadConfigWithDiff.
+//
+//export ValidateConfig
+func ValidateConfig(configPath *C.char) *C.char {
+	if configPath == nil {
+		return C.CString("ERROR: config path cannot be null")
+	}
+
+	if _, err := server.ProcessConfigFile(C.GoString(configPath)); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	return C.CString("OK")
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// Test ReloadConfigWithDiff reports a changed debug flag after reloading a
+// config file that flips it.
+func TestReloadConfigWithDiff_ReportsChangedField(t *testing.T) {
+	port := 14480
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	configPath := filepath.Join(t.TempDir(), "reload.conf")
+	configBody := []byte("port: " + strconv.Itoa(port) + "\ndebug: true\n")
+	if err := os.WriteFile(configPath, configBody, 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	pathCStr := cString(configPath)
+	defer cFree(pathCStr)
+
+	response := goStringFree(ReloadConfigWithDiff(pathCStr))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success reloading config, got: %s", response)
+	}
+
+	var report configReloadReport
+	if err := json.Unmarshal([]byte(response), &report); err != nil {
+		t.Fatalf("Failed to parse config reload report: %v", err)
+	}
+	if len(report.ReloadErrors) != 0 {
+		t.Fatalf("Expected no reload errors, got: %v", report.ReloadErrors)
+	}
+	change, exists := report.Changed["debug"]
+	if !exists {
+		t.Fatal("Expected 'debug' to appear in the changed fields")
+	}
+	if change.New != true {
+		t.Errorf("Expected debug to change to true, got: %v", change.New)
+	}
+}
+
+// Test ReloadConfigInline reports a changed field from an inline JSON blob.
+func TestReloadConfigInline_ReportsChangedField(t *testing.T) {
+	port := 14481
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	inline := cString(`{"host":"127.0.0.1","port":` + strconv.Itoa(port) + `,"debug":true}`)
+	defer cFree(inline)
+
+	response := goStringFree(ReloadConfigInline(inline))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success reloading inline config, got: %s", response)
+	}
+
+	var report configReloadReport
+	if err := json.Unmarshal([]byte(response), &report); err != nil {
+		t.Fatalf("Failed to parse config reload report: %v", err)
+	}
+	if _, exists := report.Changed["debug"]; !exists {
+		t.Fatal("Expected 'debug' to appear in the changed fields")
+	}
+}
+
+// Test ValidateConfig accepts a well-formed config file without applying it.
+func TestValidateConfig_WellFormed(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "valid.conf")
+	if err := os.WriteFile(configPath, []byte("port: 14482\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	pathCStr := cString(configPath)
+	defer cFree(pathCStr)
+
+	response := goStringFree(ValidateConfig(pathCStr))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success validating a well-formed config, got: %s", response)
+	}
+}
+
+// Test ValidateConfig surfaces a parse error for a malformed config file.
+func TestValidateConfig_Malformed(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "invalid.conf")
+	if err := os.WriteFile(configPath, []byte("this is not valid nats config {{{"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	pathCStr := cString(configPath)
+	defer cFree(pathCStr)
+
+	response := goStringFree(ValidateConfig(pathCStr))
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error validating a malformed config file")
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// serviceRegistryPrefix is the subject prefix under which registered
+// services are discoverable, mirroring the Consul-style "$SRV.>" convention.
+const serviceRegistryPrefix = "$SRV"
+
+// ServiceRegistration describes a logical service registered on top of the
+// running NATS server.
+type ServiceRegistration struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Subject  string            `json:"subject"`
+	Meta     map[string]string `json:"meta,omitempty"`
+	TTL      time.Duration     `json:"-"`
+	lastBeat time.Time
+}
+
+// ServiceFilter narrows DiscoverServices results.
+type ServiceFilter struct {
+	Name string            `json:"name"`
+	Meta map[string]string `json:"meta"`
+}
+
+var (
+	serviceRegistryMu      sync.Mutex
+	serviceRegistry        = make(map[string]*ServiceRegistration)
+	serviceRegistrySeq     uint64
+	serviceRegistryReaper  sync.Once
+	serviceRegistryStopped = make(chan struct{})
+)
+
+// startServiceRegistryReaper launches the background goroutine that expires
+// registrations whose heartbeat TTL has lapsed. It only ever runs once per
+// process.
+func startServiceRegistryReaper() {
+	serviceRegistryReaper.Do(func() {
+		go func() {
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					reapExpiredServices()
+				case <-serviceRegistryStopped:
+					return
+				}
+			}
+		}()
+	})
+}
+
+func reapExpiredServices() {
+	serviceRegistryMu.Lock()
+	defer serviceRegistryMu.Unlock()
+
+	now := time.Now()
+	for id, reg := range serviceRegistry {
+		if reg.TTL > 0 && now.Sub(reg.lastBeat) > reg.TTL {
+			delete(serviceRegistry, id)
+		}
+	}
+}
+
+// registrationSubject builds the well-known discovery subject for a service.
+func registrationSubject(name, id string) string {
+	return fmt.Sprintf("%s.%s.%s", serviceRegistryPrefix, name, id)
+}
+
+//export RegisterService
+func RegisterService(name *C.char, subject *C.char, metaJson *C.char) *C.char {
+	if name == nil || subject == nil {
+		return C.CString("ERROR: name and subject cannot be null")
+	}
+
+	svcName := C.GoString(name)
+	svcSubject := C.GoString(subject)
+	if svcName == "" || svcSubject == "" {
+		return C.CString("ERROR: name and subject cannot be empty")
+	}
+
+	var meta map[string]string
+	if metaJson != nil {
+		metaStr := C.GoString(metaJson)
+		if metaStr != "" {
+			if err := json.Unmarshal([]byte(metaStr), &meta); err != nil {
+				return C.CString(fmt.Sprintf("ERROR: Failed to parse metadata: %v", err))
+			}
+		}
+	}
+
+	startServiceRegistryReaper()
+
+	serviceRegistryMu.Lock()
+	serviceRegistrySeq++
+	id := fmt.Sprintf("%s-%d-%d", svcName, time.Now().UnixNano(), serviceRegistrySeq)
+	reg := &ServiceRegistration{
+		ID:       id,
+		Name:     svcName,
+		Subject:  svcSubject,
+		Meta:     meta,
+		TTL:      30 * time.Second,
+		lastBeat: time.Now(),
+	}
+	serviceRegistry[id] = reg
+	serviceRegistryMu.Unlock()
+
+	response := struct {
+		ID      string `json:"id"`
+		Subject string `json:"discovery_subject"`
+	}{
+		ID:      id,
+		Subject: registrationSubject(svcName, id),
+	}
+
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal registration: %v", err))
+	}
+
+	return C.CString(string(jsonBytes))
+}
+
+//export HeartbeatService
+func HeartbeatService(id *C.char) *C.char {
+	if id == nil {
+		return C.CString("ERROR: id cannot be null")
+	}
+
+	svcID := C.GoString(id)
+
+	serviceRegistryMu.Lock()
+	defer serviceRegistryMu.Unlock()
+
+	reg, exists := serviceRegistry[svcID]
+	if !exists {
+		return C.CString(fmt.Sprintf("ERROR: Service %q not found", svcID))
+	}
+	reg.lastBeat = time.Now()
+
+	return C.CString("OK")
+}
+
+//export DeregisterService
+func DeregisterService(id *C.char) *C.char {
+	if id == nil {
+		return C.CString("ERROR: id cannot be null")
+	}
+
+	svcID := C.GoString(id)
+
+	serviceRegistryMu.Lock()
+	defer serviceRegistryMu.Unlock()
+
+	if _, exists := serviceRegistry[svcID]; !exists {
+		return C.CString(fmt.Sprintf("ERROR: Service %q not found", svcID))
+	}
+	delete(serviceRegistry, svcID)
+
+	return C.CString("OK")
+}
+
+// matchesFilter reports whether a registration satisfies the given filter.
+// An empty filter field matches everything.
+func (reg *ServiceRegistration) matchesFilter(filter *ServiceFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.Name != "" && filter.Name != reg.Name {
+		return false
+	}
+	for k, v := range filter.Meta {
+		if reg.Meta[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+//export DiscoverServices
+func DiscoverServices(filterJson *C.char) *C.char {
+	var filter *ServiceFilter
+	if filterJson != nil {
+		filterStr := C.GoString(filterJson)
+		if filterStr != "" {
+			filter = &ServiceFilter{}
+			if err := json.Unmarshal([]byte(filterStr), filter); err != nil {
+				return C.CString(fmt.Sprintf("ERROR: Failed to parse filter: %v", err))
+			}
+		}
+	}
+
+	serviceRegistryMu.Lock()
+	results := make([]*ServiceRegistration, 0, len(serviceRegistry))
+	for _, reg := range serviceRegistry {
+		if reg.matchesFilter(filter) {
+			results = append(results, reg)
+		}
+	}
+	serviceRegistryMu.Unlock()
+
+	jsonBytes, err := json.Marshal(results)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal services: %v", err))
+	}
+
+	return C.CString(string(jsonBytes))
+}
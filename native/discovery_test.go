@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func resetServiceRegistry() {
+	serviceRegistryMu.Lock()
+	serviceRegistry = make(map[string]*ServiceRegistration)
+	serviceRegistryMu.Unlock()
+}
+
+// Test RegisterService followed by DiscoverServices
+func TestRegisterService_ThenDiscover(t *testing.T) {
+	resetServiceRegistry()
+
+	name := cString("orders")
+	subject := cString("orders.process")
+	meta := cString(`{"region":"us-east"}`)
+	defer cFree(name)
+	defer cFree(subject)
+	defer cFree(meta)
+
+	response := goStringFree(RegisterService(name, subject, meta))
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success, got error: %s", response)
+	}
+
+	var reg struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(response), &reg); err != nil {
+		t.Fatalf("Failed to parse registration response: %v", err)
+	}
+	if reg.ID == "" {
+		t.Fatal("Expected non-empty service ID")
+	}
+
+	filter := cString(`{"name":"orders"}`)
+	defer cFree(filter)
+
+	discoverResponse := goStringFree(DiscoverServices(filter))
+
+	var services []ServiceRegistration
+	if err := json.Unmarshal([]byte(discoverResponse), &services); err != nil {
+		t.Fatalf("Failed to parse discovery response: %v", err)
+	}
+	if len(services) != 1 {
+		t.Fatalf("Expected 1 service, got %d", len(services))
+	}
+	if services[0].Meta["region"] != "us-east" {
+		t.Errorf("Expected meta region 'us-east', got %q", services[0].Meta["region"])
+	}
+}
+
+// Test DeregisterService removes a registration from discovery
+func TestDeregisterService(t *testing.T) {
+	resetServiceRegistry()
+
+	name := cString("billing")
+	subject := cString("billing.invoice")
+	defer cFree(name)
+	defer cFree(subject)
+
+	response := goStringFree(RegisterService(name, subject, nil))
+
+	var reg struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(response), &reg); err != nil {
+		t.Fatalf("Failed to parse registration response: %v", err)
+	}
+
+	id := cString(reg.ID)
+	defer cFree(id)
+
+	deregResponse := goStringFree(DeregisterService(id))
+
+	if isErrorResponse(deregResponse) {
+		t.Fatalf("Expected success, got error: %s", deregResponse)
+	}
+
+	// Deregistering again should fail since the service is gone.
+	deregAgainResponse := goStringFree(DeregisterService(id))
+
+	if !isErrorResponse(deregAgainResponse) {
+		t.Fatal("Expected error deregistering an already-removed service")
+	}
+}
+
+// Test DiscoverServices with no registrations
+func TestDiscoverServices_Empty(t *testing.T) {
+	resetServiceRegistry()
+
+	response := goStringFree(DiscoverServices(nil))
+
+	var services []ServiceRegistration
+	if err := json.Unmarshal([]byte(response), &services); err != nil {
+		t.Fatalf("Failed to parse discovery response: %v", err)
+	}
+	if len(services) != 0 {
+		t.Fatalf("Expected 0 services, got %d", len(services))
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+/*
+#include <stdlib.h>
+
+typedef void (*event_callback_fn)(char* eventType, char* payload);
+
+static inline void call_event_callback(event_callback_fn cb, char* eventType, char* payload) {
+    cb(eventType, payload);
+}
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Event mask bits for RegisterEventCallback, matching the subject groups
+// subscribed to below. Callers combine these with bitwise OR.
+const (
+	EventConnect = 1 << iota
+	EventDisconnect
+	EventAccountUpdate
+	EventJSStreamCreated
+	EventJSStreamDeleted
+	EventJSStreamLeaderChange
+	EventJSConsumerCreated
+	EventJSConsumerDeleted
+	EventJSConsumerLeaderChange
+	EventServerStatz
+	EventSlowConsumer
+	EventAuthError
+	EventLameDuck
+)
+
+// eventSubjectMask pairs a mask bit with the $SYS/$JS subject it subscribes
+// to, so eventSubjectsForMask below is just a table scan.
+type eventSubjectMask struct {
+	bit     int
+	subject string
+	label   string
+}
+
+var eventSubjectMasks = []eventSubjectMask{
+	{EventConnect, "$SYS.ACCOUNT.*.CONNECT", "CONNECT"},
+	{EventDisconnect, "$SYS.ACCOUNT.*.DISCONNECT", "DISCONNECT"},
+	{EventAccountUpdate, "$SYS.ACCOUNT.*.CLAIMS.UPDATE", "ACCOUNT_UPDATE"},
+	{EventJSStreamCreated, "$JS.EVENT.ADVISORY.STREAM.CREATED.*", "JS_STREAM_CREATED"},
+	{EventJSStreamDeleted, "$JS.EVENT.ADVISORY.STREAM.DELETED.*", "JS_STREAM_DELETED"},
+	{EventJSStreamLeaderChange, "$JS.EVENT.ADVISORY.STREAM.LEADER_ELECTED.*", "JS_STREAM_LEADER_CHANGE"},
+	{EventJSConsumerCreated, "$JS.EVENT.ADVISORY.CONSUMER.CREATED.*.*", "JS_CONSUMER_CREATED"},
+	{EventJSConsumerDeleted, "$JS.EVENT.ADVISORY.CONSUMER.DELETED.*.*", "JS_CONSUMER_DELETED"},
+	{EventJSConsumerLeaderChange, "$JS.EVENT.ADVISORY.CONSUMER.LEADER_ELECTED.*.*", "JS_CONSUMER_LEADER_CHANGE"},
+	{EventServerStatz, "$SYS.SERVER.*.STATSZ", "SERVER_STATZ"},
+	{EventSlowConsumer, "$SYS.SERVER.*.CLIENT.SLOW_CONSUMER", "SLOW_CONSUMER"},
+	{EventAuthError, "$SYS.SERVER.*.CLIENT.AUTH.ERROR", "AUTH_ERROR"},
+	{EventLameDuck, "$SYS.SERVER.*.LAMEDUCK", "LAMEDUCK"},
+}
+
+// eventWorkerCount bounds how many goroutines may be invoking the callback
+// concurrently, so a slow .NET-side handler can't let the event queue grow
+// unbounded - excess events are dropped instead (see eventDropCount).
+const eventWorkerCount = 4
+
+// eventQueueCapacity is the bound on queued-but-undelivered events before
+// publishEvent starts dropping the oldest one to make room for the newest.
+const eventQueueCapacity = 1024
+
+type eventItem struct {
+	eventType string
+	payload   []byte
+}
+
+var (
+	eventMu       sync.Mutex
+	eventCallback C.event_callback_fn
+	eventConn     *nats.Conn
+	eventSubs     []*nats.Subscription
+	eventQueue    chan eventItem
+	eventQuit     chan struct{}
+	eventDropped  uint64
+)
+
+// publishEvent enqueues an event for delivery, dropping the oldest queued
+// event if the queue is full rather than blocking the NATS subscription's
+// dispatch goroutine. queue is passed in rather than read off the package
+// global: the subscription closure that calls this runs concurrently with
+// UnregisterEventCallback, which nils eventQueue out under eventMu once it
+// closes the subscriptions - reading the global here unsynchronized would
+// race with that nil-out and could hand publishEvent a nil channel, where
+// every send/receive case blocks forever and the select spins freely.
+func publishEvent(queue chan eventItem, eventType string, payload []byte) {
+	item := eventItem{eventType: eventType, payload: payload}
+	for {
+		select {
+		case queue <- item:
+			return
+		default:
+		}
+		select {
+		case <-queue:
+			atomic.AddUint64(&eventDropped, 1)
+		default:
+		}
+	}
+}
+
+func runEventWorker(queue chan eventItem, quit chan struct{}) {
+	for {
+		select {
+		case <-quit:
+			return
+		case item := <-queue:
+			eventMu.Lock()
+			cb := eventCallback
+			eventMu.Unlock()
+			if cb == nil {
+				continue
+			}
+
+			cType := C.CString(item.eventType)
+			cPayload := C.CString(string(item.payload))
+			C.call_event_callback(cb, cType, cPayload)
+			C.free(unsafe.Pointer(cType))
+			C.free(unsafe.Pointer(cPayload))
+		}
+	}
+}
+
+// RegisterEventCallback opens a system-account client connection to the
+// current server and subscribes to the $SYS.>/$JS.EVENT.ADVISORY.> subjects
+// selected by mask, invoking cb with each event's type and JSON payload on
+// a bounded worker pool. Only one callback may be registered at a time;
+// call UnregisterEventCallback first to replace it.
+//
+//export RegisterEventCallback
+func RegisterEventCallback(cb C.event_callback_fn, mask C.int) *C.char {
+	if cb == nil {
+		return C.CString("ERROR: callback cannot be null")
+	}
+
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	eventMu.Lock()
+	defer eventMu.Unlock()
+
+	if eventCallback != nil {
+		return C.CString("ERROR: An event callback is already registered; call UnregisterEventCallback first")
+	}
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to connect to server: %v", err))
+	}
+
+	queue := make(chan eventItem, eventQueueCapacity)
+	quit := make(chan struct{})
+
+	maskInt := int(mask)
+	var subs []*nats.Subscription
+	for _, entry := range eventSubjectMasks {
+		if maskInt&entry.bit == 0 {
+			continue
+		}
+		label := entry.label
+		sub, err := nc.Subscribe(entry.subject, func(msg *nats.Msg) {
+			publishEvent(queue, label, msg.Data)
+		})
+		if err != nil {
+			for _, s := range subs {
+				s.Unsubscribe()
+			}
+			nc.Close()
+			return C.CString(fmt.Sprintf("ERROR: Failed to subscribe to %s: %v", entry.subject, err))
+		}
+		subs = append(subs, sub)
+	}
+
+	eventCallback = cb
+	eventConn = nc
+	eventSubs = subs
+	eventQueue = queue
+	eventQuit = quit
+	atomic.StoreUint64(&eventDropped, 0)
+
+	for i := 0; i < eventWorkerCount; i++ {
+		go runEventWorker(queue, quit)
+	}
+
+	return C.CString("OK")
+}
+
+// UnregisterEventCallback stops delivering events, unsubscribes from every
+// $SYS/$JS subject RegisterEventCallback subscribed to, and closes the
+// system-account connection.
+//
+//export UnregisterEventCallback
+func UnregisterEventCallback() *C.char {
+	eventMu.Lock()
+	defer eventMu.Unlock()
+
+	if eventCallback == nil {
+		return C.CString("ERROR: No event callback is registered")
+	}
+
+	for _, sub := range eventSubs {
+		sub.Unsubscribe()
+	}
+	if eventConn != nil {
+		eventConn.Close()
+	}
+	close(eventQuit)
+
+	eventCallback = nil
+	eventConn = nil
+	eventSubs = nil
+	eventQueue = nil
+	eventQuit = nil
+
+	return C.CString("OK")
+}
+
+// GetEventDropCount returns the number of events dropped because the
+// delivery queue was full when they arrived.
+//
+//export GetEventDropCount
+func GetEventDropCount() *C.char {
+	count := atomic.LoadUint64(&eventDropped)
+	jsonBytes, err := json.Marshal(map[string]uint64{"dropped": count})
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal drop count: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Test RegisterEventCallback delivers a CONNECT event when a client
+// connects, and that UnregisterEventCallback stops delivery cleanly.
+func TestRegisterEventCallback_DeliversConnectEvent(t *testing.T) {
+	port := 14380
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	resetRecordedEvents()
+
+	response := goStringFree(RegisterEventCallback(testEventCallbackPtr(), cInt(EventConnect)))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success registering event callback, got: %s", response)
+	}
+	defer func() {
+		cFree(UnregisterEventCallback())
+	}()
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("Failed to connect test client: %v", err)
+	}
+	defer nc.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if recordedEventCount() > 0 {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for a CONNECT event")
+}
+
+// Test RegisterEventCallback rejects a second registration while one is
+// already active.
+func TestRegisterEventCallback_RejectsDoubleRegistration(t *testing.T) {
+	port := 14381
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	response := goStringFree(RegisterEventCallback(testEventCallbackPtr(), cInt(EventConnect)))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success registering event callback, got: %s", response)
+	}
+	defer func() {
+		cFree(UnregisterEventCallback())
+	}()
+
+	secondResponse := goStringFree(RegisterEventCallback(testEventCallbackPtr(), cInt(EventConnect)))
+	if !isErrorResponse(secondResponse) {
+		t.Fatal("Expected error registering a second event callback concurrently")
+	}
+}
+
+// Test GetEventDropCount reports zero when nothing has been dropped.
+func TestGetEventDropCount_InitiallyZero(t *testing.T) {
+	response := goStringFree(GetEventDropCount())
+
+	if response != `{"dropped":0}` {
+		t.Errorf("Expected zero drop count, got: %s", response)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+/*
+#include <stdlib.h>
+
+extern void recordedEventCallback(char* eventType, char* payload);
+*/
+import "C"
+import "sync"
+
+// recordedEventCallback and the bookkeeping around it exist only to give
+// event_stream_test.go an event_callback_fn it can hand to
+// RegisterEventCallback without itself importing "C" - cgo is unavailable
+// in _test.go files, so the callback and its C function-pointer cast live
+// here instead.
+var (
+	recordedEventsMu sync.Mutex
+	recordedEvents   []string
+)
+
+//export recordedEventCallback
+func recordedEventCallback(eventType *C.char, payload *C.char) {
+	recordedEventsMu.Lock()
+	recordedEvents = append(recordedEvents, C.GoString(eventType))
+	recordedEventsMu.Unlock()
+}
+
+// testEventCallbackPtr returns recordedEventCallback as the C function
+// pointer type RegisterEventCallback expects.
+func testEventCallbackPtr() C.event_callback_fn {
+	return C.event_callback_fn(C.recordedEventCallback)
+}
+
+func resetRecordedEvents() {
+	recordedEventsMu.Lock()
+	recordedEvents = nil
+	recordedEventsMu.Unlock()
+}
+
+func recordedEventCount() int {
+	recordedEventsMu.Lock()
+	defer recordedEventsMu.Unlock()
+	return len(recordedEvents)
+}
@@ -0,0 +1,312 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// HTTPGatewayConfig is StartHTTPGateway's input shape: a bridge that lets
+// non-NATS producers/consumers (webhooks, edge functions, shell scripts)
+// publish and subscribe over plain HTTP instead of linking a NATS client.
+type HTTPGatewayConfig struct {
+	Addr        string `json:"addr"` // default "127.0.0.1:0"
+	TLSCert     string `json:"tls_cert"`
+	TLSKey      string `json:"tls_key"`
+	TLSCACert   string `json:"tls_ca_cert"`
+	TLSVerify   bool   `json:"tls_verify"`
+	BearerToken string `json:"bearer_token"` // if set, required on every request
+	RequireJWT  bool   `json:"require_jwt"`  // if set, bearer token must decode as a valid user JWT
+}
+
+// httpGateway pairs a running HTTP listener with the NATS connection it
+// bridges requests to, keyed by an opaque handle the same way the
+// managed-servers lifecycle API (server_manager.go) keys the handle it
+// hands back from StartManagedServer. Unlike a server instance handle,
+// there's no natsServers-equivalent table an httpGateway needs to share,
+// since nothing else in this package addresses a gateway by handle.
+type httpGateway struct {
+	Handle string
+	srv    *http.Server
+	ln     net.Listener
+	nc     *nats.Conn
+}
+
+var (
+	httpGatewaysMu  sync.Mutex
+	httpGateways    = make(map[string]*httpGateway)
+	httpGatewaysSeq uint64
+)
+
+func nextHTTPGatewayHandle() string {
+	httpGatewaysSeq++
+	return fmt.Sprintf("httpgw-%d", httpGatewaysSeq)
+}
+
+// StartHTTPGateway starts an HTTP server exposing POST /pub/<subject> and
+// GET /sub/<subject> against the current embedded NATS server, and returns
+// a handle for StopHTTPGateway plus the address actually bound (useful
+// when Addr asks for an ephemeral port).
+//
+//export StartHTTPGateway
+func StartHTTPGateway(configJson *C.char) *C.char {
+	var cfg HTTPGatewayConfig
+	if configJson != nil {
+		if cfgStr := C.GoString(configJson); cfgStr != "" {
+			if err := json.Unmarshal([]byte(cfgStr), &cfg); err != nil {
+				return C.CString(fmt.Sprintf("ERROR: Failed to parse gateway configuration: %v", err))
+			}
+		}
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = "127.0.0.1:0"
+	}
+
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to connect to server: %v", err))
+	}
+
+	ln, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		nc.Close()
+		return C.CString(fmt.Sprintf("ERROR: Failed to listen on %q: %v", cfg.Addr, err))
+	}
+
+	if cfg.TLSCert != "" && cfg.TLSKey != "" {
+		tlsConfig, err := server.GenTLSConfig(&server.TLSConfigOpts{
+			CertFile: cfg.TLSCert,
+			KeyFile:  cfg.TLSKey,
+			CaFile:   cfg.TLSCACert,
+			Verify:   cfg.TLSVerify,
+		})
+		if err != nil {
+			ln.Close()
+			nc.Close()
+			return C.CString(fmt.Sprintf("ERROR: Failed to configure TLS: %v", err))
+		}
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	gw := &httpGateway{nc: nc, ln: ln}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pub/", gatewayAuthMiddleware(cfg, gw.handlePublish))
+	mux.HandleFunc("/sub/", gatewayAuthMiddleware(cfg, gw.handleSubscribe))
+	gw.srv = &http.Server{Handler: mux}
+
+	httpGatewaysMu.Lock()
+	gw.Handle = nextHTTPGatewayHandle()
+	httpGateways[gw.Handle] = gw
+	httpGatewaysMu.Unlock()
+
+	go gw.srv.Serve(ln)
+
+	response := struct {
+		Handle string `json:"handle"`
+		Addr   string `json:"addr"`
+	}{Handle: gw.Handle, Addr: ln.Addr().String()}
+
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal gateway handle: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// StopHTTPGateway shuts down the HTTP gateway identified by handle and
+// closes its NATS connection.
+//
+//export StopHTTPGateway
+func StopHTTPGateway(handle *C.char) *C.char {
+	if handle == nil {
+		return C.CString("ERROR: handle cannot be null")
+	}
+	h := C.GoString(handle)
+
+	httpGatewaysMu.Lock()
+	gw, exists := httpGateways[h]
+	if exists {
+		delete(httpGateways, h)
+	}
+	httpGatewaysMu.Unlock()
+
+	if !exists {
+		return C.CString(fmt.Sprintf("ERROR: No HTTP gateway for handle %q", h))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := gw.srv.Shutdown(ctx); err != nil {
+		gw.srv.Close()
+	}
+	gw.nc.Close()
+
+	return C.CString("OK")
+}
+
+// gatewayAuthMiddleware wraps next with cfg's bearer-token check, if one is
+// configured - a shared-secret comparison by default, or (RequireJWT) a
+// check that the token decodes as a well-formed user JWT signed by an
+// account the current server can look up. Note: this is a lighter check
+// than full nats-server client authentication (it doesn't verify the JWT's
+// signature chain against a trusted operator), since the gateway
+// authenticates the HTTP caller, not a NATS client connection.
+func gatewayAuthMiddleware(cfg HTTPGatewayConfig, next http.HandlerFunc) http.HandlerFunc {
+	if cfg.BearerToken == "" && !cfg.RequireJWT {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		if cfg.RequireJWT {
+			claims, err := jwt.DecodeUserClaims(token)
+			if err != nil {
+				http.Error(w, "invalid JWT", http.StatusUnauthorized)
+				return
+			}
+			serverMu.Lock()
+			srv, exists := natsServers[currentPort]
+			serverMu.Unlock()
+			if exists && srv != nil {
+				if _, err := srv.LookupAccount(claims.IssuerAccount); err != nil {
+					http.Error(w, "unknown issuer account", http.StatusUnauthorized)
+					return
+				}
+			}
+		} else if token != cfg.BearerToken {
+			http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func subjectFromPath(r *http.Request, prefix string) (string, error) {
+	subject := strings.TrimPrefix(r.URL.Path, prefix)
+	if subject == "" {
+		return "", fmt.Errorf("missing subject")
+	}
+	return subject, nil
+}
+
+// writeSSEEvent writes one Server-Sent Event of the given event type,
+// splitting data on '\n' into one "data: " line per line as the SSE spec
+// requires - a single "data: %s" line would silently truncate any payload
+// containing a newline at the client.
+func writeSSEEvent(w io.Writer, event string, data []byte) {
+	fmt.Fprintf(w, "event: %s\n", event)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	fmt.Fprint(w, "\n")
+}
+
+// handlePublish implements POST /pub/<subject>: the request body (JSON or
+// raw bytes) is forwarded verbatim as the message payload.
+func (gw *httpGateway) handlePublish(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	subject, err := subjectFromPath(r, "/pub/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := gw.nc.Publish(subject, body); err != nil {
+		http.Error(w, fmt.Sprintf("failed to publish: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok", "subject": subject})
+}
+
+// handleSubscribe implements GET /sub/<subject>: a Server-Sent-Events
+// stream of every message published to subject until the client
+// disconnects.
+func (gw *httpGateway) handleSubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	subject, err := subjectFromPath(r, "/sub/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	msgs := make(chan *nats.Msg, 64)
+	sub, err := gw.nc.Subscribe(subject, func(msg *nats.Msg) {
+		select {
+		case msgs <- msg:
+		default:
+			// Drop messages the client isn't keeping up with rather than
+			// block the NATS subscription's delivery goroutine.
+		}
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to subscribe: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg := <-msgs:
+			writeSSEEvent(w, "message", msg.Data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// startHTTPGateway is a small helper starting a gateway against the given
+// server and returning its handle and bound address.
+func startHTTPGateway(t *testing.T, configJson string) (handle string, addr string) {
+	t.Helper()
+	configCStr := cString(configJson)
+	defer cFree(configCStr)
+
+	response := goStringFree(StartHTTPGateway(configCStr))
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success starting HTTP gateway, got: %s", response)
+	}
+
+	var handleResp struct {
+		Handle string `json:"handle"`
+		Addr   string `json:"addr"`
+	}
+	if err := json.Unmarshal([]byte(response), &handleResp); err != nil {
+		t.Fatalf("Failed to parse gateway handle response: %v", err)
+	}
+	return handleResp.Handle, handleResp.Addr
+}
+
+func stopHTTPGateway(t *testing.T, handle string) {
+	t.Helper()
+	handleCStr := cString(handle)
+	defer cFree(handleCStr)
+	result := StopHTTPGateway(handleCStr)
+	cFree(result)
+}
+
+// Test POST /pub/<subject> forwards the request body to a NATS subscriber.
+func TestHTTPGateway_Publish(t *testing.T) {
+	port := 14520
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	handle, addr := startHTTPGateway(t, `{"addr":"127.0.0.1:0"}`)
+	defer stopHTTPGateway(t, handle)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer nc.Close()
+
+	received := make(chan []byte, 1)
+	sub, err := nc.Subscribe("gateway.test", func(msg *nats.Msg) {
+		received <- msg.Data
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+	nc.Flush()
+
+	resp, err := http.Post("http://"+addr+"/pub/gateway.test", "application/json", bytes.NewBufferString(`{"hello":"world"}`))
+	if err != nil {
+		t.Fatalf("Failed to POST: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("Expected 202 Accepted, got %d: %s", resp.StatusCode, body)
+	}
+
+	select {
+	case data := <-received:
+		if string(data) != `{"hello":"world"}` {
+			t.Errorf("Expected forwarded body, got: %s", data)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for message delivery")
+	}
+}
+
+// Test GET /sub/<subject> streams published messages as Server-Sent Events.
+func TestHTTPGateway_Subscribe(t *testing.T) {
+	port := 14521
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	handle, addr := startHTTPGateway(t, `{"addr":"127.0.0.1:0"}`)
+	defer stopHTTPGateway(t, handle)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer nc.Close()
+
+	resp, err := http.Get("http://" + addr + "/sub/gateway.sse")
+	if err != nil {
+		t.Fatalf("Failed to GET: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 OK, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected Content-Type 'text/event-stream', got: %s", ct)
+	}
+
+	time.Sleep(100 * time.Millisecond) // let the subscription register
+	if err := nc.Publish("gateway.sse", []byte("hello")); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		if strings.HasPrefix(line, "data: ") {
+			if strings.TrimSpace(strings.TrimPrefix(line, "data: ")) != "hello" {
+				t.Errorf("Expected SSE data 'hello', got: %q", line)
+			}
+			return
+		}
+	}
+	t.Fatal("Timed out waiting for SSE event")
+}
+
+// Test GET /sub/<subject> splits a multi-line payload across one "data: "
+// line per line, per the SSE spec, instead of truncating at the first
+// newline.
+func TestHTTPGateway_Subscribe_MultiLinePayload(t *testing.T) {
+	port := 14523
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	handle, addr := startHTTPGateway(t, `{"addr":"127.0.0.1:0"}`)
+	defer stopHTTPGateway(t, handle)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer nc.Close()
+
+	resp, err := http.Get("http://" + addr + "/sub/gateway.multiline")
+	if err != nil {
+		t.Fatalf("Failed to GET: %v", err)
+	}
+	defer resp.Body.Close()
+
+	time.Sleep(100 * time.Millisecond) // let the subscription register
+	if err := nc.Publish("gateway.multiline", []byte("line one\nline two")); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	var dataLines []string
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			break
+		}
+		if line == "\n" && len(dataLines) > 0 {
+			break
+		}
+		if strings.HasPrefix(line, "data: ") {
+			dataLines = append(dataLines, strings.TrimSuffix(strings.TrimPrefix(line, "data: "), "\n"))
+		}
+	}
+
+	if len(dataLines) != 2 || dataLines[0] != "line one" || dataLines[1] != "line two" {
+		t.Fatalf("Expected two 'data: ' lines for the multi-line payload, got: %q", dataLines)
+	}
+}
+
+// Test the bearer-token auth middleware rejects a missing or wrong token.
+func TestHTTPGateway_BearerTokenAuth(t *testing.T) {
+	port := 14522
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	handle, addr := startHTTPGateway(t, `{"addr":"127.0.0.1:0","bearer_token":"s3cret"}`)
+	defer stopHTTPGateway(t, handle)
+
+	resp, err := http.Post("http://"+addr+"/pub/gateway.auth", "application/json", bytes.NewBufferString(`{}`))
+	if err != nil {
+		t.Fatalf("Failed to POST: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 Unauthorized without a token, got %d", resp.StatusCode)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "http://"+addr+"/pub/gateway.auth", bytes.NewBufferString(`{}`))
+	if err != nil {
+		t.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer s3cret")
+	authedResp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to POST with token: %v", err)
+	}
+	defer authedResp.Body.Close()
+	if authedResp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(authedResp.Body)
+		t.Fatalf("Expected 202 Accepted with a valid token, got %d: %s", authedResp.StatusCode, body)
+	}
+}
+
+// Test StopHTTPGateway rejects an unknown handle.
+func TestStopHTTPGateway_UnknownHandle(t *testing.T) {
+	handleCStr := cString("does-not-exist")
+	defer cFree(handleCStr)
+
+	response := goStringFree(StopHTTPGateway(handleCStr))
+
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error stopping an unknown gateway handle")
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	"unsafe"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// Scope note: StartServerInstance/ShutdownInstance/server_manager.go's
+// StartManagedServer and friends let a process run and control several
+// server instances' lifecycles concurrently (start, stop, drain, wait-
+// ready, select), all keyed consistently by port-string handle into
+// natsServers/natsServerOpts/fastStates. Every other exported feature
+// added on top of this binding layer (JetStream admin, JWT/NKey
+// lifecycle, the HTTP gateway, monitoring, NRG, etc.) still only operates
+// against whichever single instance is "current" (currentPort) - they
+// take no handle/port parameter of their own. Call SelectManagedServer or
+// UpdateAndReloadConfig to make a given instance current before using one
+// of those calls against it; don't expect them to address multiple
+// instances independently without an explicit "Select" first.
+
+// resolveInstanceLocked looks up the running server for an explicit handle -
+// its listening port, formatted as a string, the same identity natsServers
+// is already keyed by. Caller must hold serverMu.
+func resolveInstanceLocked(handleID *C.char) (*server.Server, int, error) {
+	if handleID == nil {
+		return nil, 0, fmt.Errorf("handle cannot be null")
+	}
+	handleStr := C.GoString(handleID)
+	port, err := strconv.Atoi(handleStr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid handle %q", handleStr)
+	}
+	srv, exists := natsServers[port]
+	if !exists || srv == nil {
+		return nil, 0, fmt.Errorf("no server for handle %q", handleStr)
+	}
+	return srv, port, nil
+}
+
+// shutdownAndForgetLocked shuts the given server down, waits up to 10
+// seconds for a clean exit, and removes it from the instance tables. Caller
+// must hold serverMu.
+func shutdownAndForgetLocked(port int, srv *server.Server) {
+	srv.Shutdown()
+
+	shutdownComplete := make(chan struct{})
+	go func() {
+		srv.WaitForShutdown()
+		close(shutdownComplete)
+	}()
+
+	select {
+	case <-shutdownComplete:
+		// Shutdown completed gracefully
+	case <-time.After(10 * time.Second):
+		// Timeout - force cleanup anyway
+	}
+
+	delete(natsServers, port)
+	delete(natsServerOpts, port)
+	clearFastState(port)
+}
+
+// callWithCurrentHandle invokes a *For function with currentPort as its
+// handle, for the legacy currentPort-based functions below that now defer
+// to the handle-based API instead of duplicating its logic.
+func callWithCurrentHandle(forFunc func(*C.char) *C.char, notRunningMsg string) *C.char {
+	serverMu.Lock()
+	port := currentPort
+	serverMu.Unlock()
+
+	if port == 0 {
+		return C.CString(notRunningMsg)
+	}
+
+	handle := C.CString(strconv.Itoa(port))
+	defer C.free(unsafe.Pointer(handle))
+	return forFunc(handle)
+}
+
+// StartServerInstance starts a new server from the given JSON configuration
+// and returns its handle explicitly, instead of only recording it as the
+// implicit "current" server the legacy currentPort-based API relies on.
+// This lets a single process embed several independently-addressed brokers
+// - test harnesses, sidecar bridging, per-tenant isolation - which the
+// single-port design forbade.
+//
+//export StartServerInstance
+func StartServerInstance(configJson *C.char) *C.char {
+	jsonStr := C.GoString(configJson)
+
+	var config ServerConfig
+	if err := json.Unmarshal([]byte(jsonStr), &config); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse configuration: %v", err))
+	}
+
+	opts := convertToNatsOptions(&config)
+	if err := createAndStartServer(opts); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	return C.CString(strconv.Itoa(opts.Port))
+}
+
+// GetServerNameFor returns the configured name of the instance identified
+// by handleID, mirroring GetServerName but without touching currentPort.
+//
+//export GetServerNameFor
+func GetServerNameFor(handleID *C.char) *C.char {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, _, err := resolveInstanceLocked(handleID)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	return C.CString(srv.Name())
+}
+
+// IsServerRunningFor reports whether the instance identified by handleID is
+// currently running. Returns "true" or "false". Reads only the atomic
+// fast-state cache populated by publishFastState, so it never contends with
+// serverMu.
+//
+//export IsServerRunningFor
+func IsServerRunningFor(handleID *C.char) *C.char {
+	if handleID == nil {
+		return C.CString("false")
+	}
+	port, err := strconv.Atoi(C.GoString(handleID))
+	if err != nil {
+		return C.CString("false")
+	}
+
+	state, exists := loadFastState(port)
+	if !exists {
+		return C.CString("false")
+	}
+	srv := state.srv.Load()
+	if srv == nil || !srv.Running() {
+		return C.CString("false")
+	}
+	return C.CString("true")
+}
+
+// waitForListeners blocks until srv is ready for core NATS connections
+// and, for any of MQTT/WebSocket that opts configures, their accept loops
+// have come up too.
+func waitForListeners(srv *server.Server, opts *server.Options, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	if !srv.ReadyForConnections(timeout) {
+		return false
+	}
+
+	for {
+		mqttReady := opts == nil || opts.MQTT.Port == 0 || srv.MQTTAddr() != nil
+		wsReady := opts == nil || opts.Websocket.Port == 0 || srv.WebsocketAddr() != nil
+		if mqttReady && wsReady {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// WaitForReadyStateFor blocks until the instance identified by handleID is
+// ready for connections - including its MQTT/WebSocket listeners, if
+// configured - with a timeout specified in seconds. Returns "true" if
+// ready, "false" if the timeout expires. serverMu is only held long enough
+// to snapshot srv/opts; the wait itself runs unlocked so it doesn't
+// serialize every other call against this one's timeout.
+//
+//export WaitForReadyStateFor
+func WaitForReadyStateFor(handleID *C.char, timeoutSeconds C.int) *C.char {
+	serverMu.Lock()
+	srv, port, err := resolveInstanceLocked(handleID)
+	var opts *server.Options
+	if err == nil {
+		opts = natsServerOpts[port]
+	}
+	serverMu.Unlock()
+
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	if waitForListeners(srv, opts, timeout) {
+		return C.CString("true")
+	}
+	return C.CString("false")
+}
+
+// IsJetStreamEnabledFor reports whether JetStream is configured for the
+// instance identified by handleID. JetStream enablement is a boot-time
+// setting a running server can't change, so this reads the bit
+// publishFastState cached at startup instead of calling srv.Varz.
+//
+//export IsJetStreamEnabledFor
+func IsJetStreamEnabledFor(handleID *C.char) *C.char {
+	if handleID == nil {
+		return C.CString("ERROR: handle cannot be null")
+	}
+	port, err := strconv.Atoi(C.GoString(handleID))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: invalid handle %q", C.GoString(handleID)))
+	}
+
+	state, exists := loadFastState(port)
+	if !exists || state.srv.Load() == nil {
+		return C.CString(fmt.Sprintf("ERROR: no server for handle %q", C.GoString(handleID)))
+	}
+
+	if state.jetstreamEnabled.Load() {
+		return C.CString("true")
+	}
+	return C.CString("false")
+}
+
+// ShutdownInstance stops the instance identified by handleID and removes it
+// from the instance tables, mirroring ShutdownServer's behavior for
+// currentPort but without disturbing any other running instance.
+//
+//export ShutdownInstance
+func ShutdownInstance(handleID *C.char) *C.char {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, port, err := resolveInstanceLocked(handleID)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	shutdownAndForgetLocked(port, srv)
+	if currentPort == port {
+		setCurrentPortLocked(0)
+	}
+
+	return C.CString("OK")
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"testing"
+)
+
+// Test StartServerInstance followed by the handle-based accessors, without
+// poking serverMu/natsServers directly the way the currentPort-based tests
+// have to.
+func TestStartServerInstance_HandleBasedAccessors(t *testing.T) {
+	configJson := cString(`{"host":"127.0.0.1","port":14330}`)
+	defer cFree(configJson)
+
+	handle := goStringFree(StartServerInstance(configJson))
+
+	if isErrorResponse(handle) {
+		t.Fatalf("Expected a handle, got error: %s", handle)
+	}
+
+	cHandle := cString(handle)
+	defer cFree(cHandle)
+	defer func() {
+		result := ShutdownInstance(cHandle)
+		cFree(result)
+	}()
+
+	running := goString(IsServerRunningFor(cHandle))
+	if running != "true" {
+		t.Errorf("Expected 'true' for a freshly started instance, got: %s", running)
+	}
+
+	ready := goString(WaitForReadyStateFor(cHandle, cInt(5)))
+	if ready != "true" {
+		t.Errorf("Expected 'true' (ready), got: %s", ready)
+	}
+
+	name := goString(GetServerNameFor(cHandle))
+	if isErrorResponse(name) {
+		t.Errorf("Expected a name (possibly empty), got error: %s", name)
+	}
+}
+
+// Test that IsJetStreamEnabledFor reports JetStream state for an explicit
+// handle, the handle-based equivalent of TestIsJetStreamEnabled_WithJetStream.
+func TestIsJetStreamEnabledFor_WithJetStream(t *testing.T) {
+	configJson := cString(`{"host":"127.0.0.1","port":14331,"jetstream":true,"jetstream_store_dir":"` + t.TempDir() + `"}`)
+	defer cFree(configJson)
+
+	handle := goStringFree(StartServerInstance(configJson))
+
+	if isErrorResponse(handle) {
+		t.Fatalf("Expected a handle, got error: %s", handle)
+	}
+
+	cHandle := cString(handle)
+	defer cFree(cHandle)
+	defer func() {
+		result := ShutdownInstance(cHandle)
+		cFree(result)
+	}()
+
+	response := goString(IsJetStreamEnabledFor(cHandle))
+	if response != "true" {
+		t.Errorf("Expected 'true' (JetStream enabled), got: %s", response)
+	}
+}
+
+// Test that the *For functions report errors for an unknown handle instead
+// of silently falling back to currentPort.
+func TestInstanceAccessors_UnknownHandle(t *testing.T) {
+	unknown := cString("999999")
+	defer cFree(unknown)
+
+	if response := goString(IsServerRunningFor(unknown)); response != "false" {
+		t.Errorf("Expected 'false' for an unknown handle, got: %s", response)
+	}
+
+	if response := goString(GetServerNameFor(unknown)); !isErrorResponse(response) {
+		t.Errorf("Expected error for an unknown handle, got: %s", response)
+	}
+
+	if response := goString(ShutdownInstance(unknown)); !isErrorResponse(response) {
+		t.Errorf("Expected error shutting down an unknown handle, got: %s", response)
+	}
+}
+
+// Test that multiple instances started via StartServerInstance are
+// independently addressable and shutting one down leaves the other running.
+func TestStartServerInstance_MultipleIndependentInstances(t *testing.T) {
+	config1 := cString(`{"host":"127.0.0.1","port":14332}`)
+	defer cFree(config1)
+	config2 := cString(`{"host":"127.0.0.1","port":14333}`)
+	defer cFree(config2)
+
+	handle1 := goString(StartServerInstance(config1))
+	handle2 := goString(StartServerInstance(config2))
+
+	cHandle1 := cString(handle1)
+	defer cFree(cHandle1)
+	cHandle2 := cString(handle2)
+	defer cFree(cHandle2)
+	defer func() {
+		result := ShutdownInstance(cHandle2)
+		cFree(result)
+	}()
+
+	shutdownResult := goString(ShutdownInstance(cHandle1))
+	if isErrorResponse(shutdownResult) {
+		t.Fatalf("Expected success shutting down first instance, got: %s", shutdownResult)
+	}
+
+	if running := goString(IsServerRunningFor(cHandle1)); running != "false" {
+		t.Errorf("Expected first instance to be stopped, got: %s", running)
+	}
+	if running := goString(IsServerRunningFor(cHandle2)); running != "true" {
+		t.Errorf("Expected second instance to still be running, got: %s", running)
+	}
+}
@@ -0,0 +1,266 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// JetStreamAccountLimits is the JSON shape SetAccountJetStreamLimits and
+// EnableAccountJetStream accept for one replica tier's resource limits,
+// mirroring server.JetStreamAccountLimits field for field.
+type JetStreamAccountLimits struct {
+	MaxMemory            int64 `json:"max_memory"`
+	MaxStore             int64 `json:"max_store"`
+	MaxStreams           int   `json:"max_streams"`
+	MaxConsumers         int   `json:"max_consumers"`
+	MaxBytesRequired     bool  `json:"max_bytes_required"`
+	MemoryMaxStreamBytes int64 `json:"memory_max_stream_bytes"`
+	StoreMaxStreamBytes  int64 `json:"store_max_stream_bytes"`
+	MaxAckPending        int   `json:"max_ack_pending"`
+}
+
+func (l JetStreamAccountLimits) toServerLimits() server.JetStreamAccountLimits {
+	return server.JetStreamAccountLimits{
+		MaxMemory:            l.MaxMemory,
+		MaxStore:             l.MaxStore,
+		MaxStreams:           l.MaxStreams,
+		MaxConsumers:         l.MaxConsumers,
+		MaxBytesRequired:     l.MaxBytesRequired,
+		MemoryMaxStreamBytes: l.MemoryMaxStreamBytes,
+		StoreMaxStreamBytes:  l.StoreMaxStreamBytes,
+		MaxAckPending:        l.MaxAckPending,
+	}
+}
+
+func jetStreamLimitsFromServer(l server.JetStreamAccountLimits) JetStreamAccountLimits {
+	return JetStreamAccountLimits{
+		MaxMemory:            l.MaxMemory,
+		MaxStore:             l.MaxStore,
+		MaxStreams:           l.MaxStreams,
+		MaxConsumers:         l.MaxConsumers,
+		MaxBytesRequired:     l.MaxBytesRequired,
+		MemoryMaxStreamBytes: l.MemoryMaxStreamBytes,
+		StoreMaxStreamBytes:  l.StoreMaxStreamBytes,
+		MaxAckPending:        l.MaxAckPending,
+	}
+}
+
+// jetStreamLimitsRequest is SetAccountJetStreamLimits/EnableAccountJetStream's
+// request body: either a flat set of limits applied to the default tier, or
+// a per-tier map keyed "R1"/"R3"/etc. for JetStream 2.10+'s tiered limits.
+type jetStreamLimitsRequest struct {
+	JetStreamAccountLimits
+	Tiers map[string]JetStreamAccountLimits `json:"tiers"`
+}
+
+func (r jetStreamLimitsRequest) toTierMap() map[string]server.JetStreamAccountLimits {
+	if len(r.Tiers) > 0 {
+		tiers := make(map[string]server.JetStreamAccountLimits, len(r.Tiers))
+		for tier, limits := range r.Tiers {
+			tiers[tier] = limits.toServerLimits()
+		}
+		return tiers
+	}
+	return map[string]server.JetStreamAccountLimits{"": r.JetStreamAccountLimits.toServerLimits()}
+}
+
+func parseJetStreamLimitsRequest(limitsJSON *C.char) (jetStreamLimitsRequest, error) {
+	var req jetStreamLimitsRequest
+	if limitsJSON == nil {
+		return req, nil
+	}
+	s := C.GoString(limitsJSON)
+	if s == "" {
+		return req, nil
+	}
+	err := json.Unmarshal([]byte(s), &req)
+	return req, err
+}
+
+func marshalJetStreamTiers(tiers map[string]server.JetStreamAccountLimits) *C.char {
+	response := make(map[string]JetStreamAccountLimits, len(tiers))
+	for tier, limits := range tiers {
+		response[tier] = jetStreamLimitsFromServer(limits)
+	}
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal effective limits: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// lookupAccountForJetStream resolves accountName against the current
+// server, returning a ready-to-use *C.char error response when it can't.
+func lookupAccountForJetStream(accountName *C.char) (*server.Account, *C.char) {
+	if accountName == nil {
+		return nil, C.CString("ERROR: account name cannot be null")
+	}
+	acctName := C.GoString(accountName)
+	if acctName == "" {
+		return nil, C.CString("ERROR: account name cannot be empty")
+	}
+
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return nil, C.CString("ERROR: Server not running")
+	}
+
+	acc, err := srv.LookupAccount(acctName)
+	if err != nil {
+		return nil, C.CString(fmt.Sprintf("ERROR: Account not found: %v", err))
+	}
+	return acc, nil
+}
+
+// SetAccountJetStreamLimits updates the named account's live JetStream
+// resource limits - memory/store/streams/consumers, optionally split across
+// replica tiers ("R1"/"R3"/etc.) for JetStream 2.10+ - without touching any
+// other account's configuration, and returns the limits the server actually
+// applied.
+//
+//export SetAccountJetStreamLimits
+func SetAccountJetStreamLimits(accountName *C.char, limitsJSON *C.char) *C.char {
+	acc, errResp := lookupAccountForJetStream(accountName)
+	if errResp != nil {
+		return errResp
+	}
+
+	req, err := parseJetStreamLimitsRequest(limitsJSON)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse limits: %v", err))
+	}
+
+	tiers := req.toTierMap()
+	if err := acc.UpdateJetStreamLimits(tiers); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to update JetStream limits: %v", err))
+	}
+
+	return marshalJetStreamTiers(tiers)
+}
+
+// EnableAccountJetStream turns on JetStream for an account that doesn't
+// already have it, with the given (optionally tiered) resource limits.
+//
+//export EnableAccountJetStream
+func EnableAccountJetStream(accountName *C.char, limitsJSON *C.char) *C.char {
+	acc, errResp := lookupAccountForJetStream(accountName)
+	if errResp != nil {
+		return errResp
+	}
+
+	req, err := parseJetStreamLimitsRequest(limitsJSON)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse limits: %v", err))
+	}
+
+	tiers := req.toTierMap()
+	if err := acc.EnableJetStream(tiers); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to enable JetStream: %v", err))
+	}
+
+	return marshalJetStreamTiers(tiers)
+}
+
+// DisableAccountJetStream turns JetStream off for the named account,
+// releasing any streams/consumers it still owns.
+//
+//export DisableAccountJetStream
+func DisableAccountJetStream(accountName *C.char) *C.char {
+	acc, errResp := lookupAccountForJetStream(accountName)
+	if errResp != nil {
+		return errResp
+	}
+
+	if err := acc.DisableJetStream(); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to disable JetStream: %v", err))
+	}
+
+	return C.CString("OK")
+}
+
+// accountJetStreamDomains tracks each account's assigned JetStream domain.
+// nats-server has no public API to change a live account's JetStream domain
+// independent of its JWT/static config - domain assignment is normally
+// baked in at account-creation time - so this is local bookkeeping that
+// SetAccountJetStreamDomain manages on the embedder's behalf and
+// GetAccountJetStreamUsage surfaces back, until the account is next
+// re-provisioned with a domain-carrying JWT or config block.
+var (
+	accountJetStreamDomainsMu sync.Mutex
+	accountJetStreamDomains   = make(map[string]string)
+)
+
+// SetAccountJetStreamDomain records domain as the named account's JetStream
+// domain. See accountJetStreamDomains for why this is bookkeeping rather
+// than a live server mutation.
+//
+//export SetAccountJetStreamDomain
+func SetAccountJetStreamDomain(accountName *C.char, domain *C.char) *C.char {
+	if accountName == nil {
+		return C.CString("ERROR: account name cannot be null")
+	}
+	acctName := C.GoString(accountName)
+	if acctName == "" {
+		return C.CString("ERROR: account name cannot be empty")
+	}
+
+	domainStr := ""
+	if domain != nil {
+		domainStr = C.GoString(domain)
+	}
+
+	accountJetStreamDomainsMu.Lock()
+	accountJetStreamDomains[acctName] = domainStr
+	accountJetStreamDomainsMu.Unlock()
+
+	return C.CString("OK")
+}
+
+// GetAccountJetStreamUsage reports the named account's current JetStream
+// resource usage (memory/store/streams/consumers, per tier) plus its
+// tracked domain, if one was set via SetAccountJetStreamDomain. Note:
+// Account.JetStreamUsage's concrete return type isn't part of the verified
+// public surface available in this build, so its result is re-marshaled
+// generically rather than referenced by Go struct field.
+//
+//export GetAccountJetStreamUsage
+func GetAccountJetStreamUsage(accountName *C.char) *C.char {
+	acc, errResp := lookupAccountForJetStream(accountName)
+	if errResp != nil {
+		return errResp
+	}
+
+	usageBytes, err := json.Marshal(acc.JetStreamUsage())
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal JetStream usage: %v", err))
+	}
+
+	var usage map[string]json.RawMessage
+	if err := json.Unmarshal(usageBytes, &usage); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal JetStream usage: %v", err))
+	}
+
+	accountJetStreamDomainsMu.Lock()
+	domain := accountJetStreamDomains[C.GoString(accountName)]
+	accountJetStreamDomainsMu.Unlock()
+	if domain != "" {
+		if domainBytes, err := json.Marshal(domain); err == nil {
+			usage["domain"] = domainBytes
+		}
+	}
+
+	jsonBytes, err := json.Marshal(usage)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal JetStream usage: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
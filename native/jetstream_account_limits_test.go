@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Test SetAccountJetStreamLimits applies a flat (single-tier) set of limits
+// and returns them as the effective limits.
+func TestSetAccountJetStreamLimits_FlatLimits(t *testing.T) {
+	port := 14500
+	srv := startJetStreamTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	srv.RegisterAccount("TENANT_A")
+
+	accountCStr := cString("TENANT_A")
+	defer cFree(accountCStr)
+	limitsCStr := cString(`{"max_memory":1048576,"max_store":2097152,"max_streams":5,"max_consumers":10}`)
+	defer cFree(limitsCStr)
+
+	response := goStringFree(SetAccountJetStreamLimits(accountCStr, limitsCStr))
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success setting JetStream limits, got: %s", response)
+	}
+
+	var tiers map[string]JetStreamAccountLimits
+	if err := json.Unmarshal([]byte(response), &tiers); err != nil {
+		t.Fatalf("Expected JSON tier map, got %q: %v", response, err)
+	}
+	limits, exists := tiers[""]
+	if !exists {
+		t.Fatal("Expected the default tier to be present")
+	}
+	if limits.MaxStreams != 5 {
+		t.Errorf("Expected max_streams 5, got: %d", limits.MaxStreams)
+	}
+}
+
+// Test SetAccountJetStreamLimits applies a per-tier map when one is given.
+func TestSetAccountJetStreamLimits_TieredLimits(t *testing.T) {
+	port := 14501
+	srv := startJetStreamTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	srv.RegisterAccount("TENANT_B")
+
+	accountCStr := cString("TENANT_B")
+	defer cFree(accountCStr)
+	limitsCStr := cString(`{"tiers":{"R1":{"max_streams":3},"R3":{"max_streams":1}}}`)
+	defer cFree(limitsCStr)
+
+	response := goStringFree(SetAccountJetStreamLimits(accountCStr, limitsCStr))
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success setting tiered JetStream limits, got: %s", response)
+	}
+
+	var tiers map[string]JetStreamAccountLimits
+	if err := json.Unmarshal([]byte(response), &tiers); err != nil {
+		t.Fatalf("Expected JSON tier map, got %q: %v", response, err)
+	}
+	if len(tiers) != 2 {
+		t.Fatalf("Expected 2 tiers, got: %d", len(tiers))
+	}
+	if tiers["R1"].MaxStreams != 3 {
+		t.Errorf("Expected R1 max_streams 3, got: %d", tiers["R1"].MaxStreams)
+	}
+}
+
+// Test EnableAccountJetStream/DisableAccountJetStream toggle an account's
+// JetStream status, and GetAccountJetStreamUsage reflects it.
+func TestEnableDisableAccountJetStream(t *testing.T) {
+	port := 14502
+	srv := startJetStreamTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	srv.RegisterAccount("TENANT_C")
+
+	accountCStr := cString("TENANT_C")
+	defer cFree(accountCStr)
+	limitsCStr := cString(`{"max_memory":1048576,"max_store":1048576}`)
+	defer cFree(limitsCStr)
+
+	enableResponse := goStringFree(EnableAccountJetStream(accountCStr, limitsCStr))
+	if isErrorResponse(enableResponse) {
+		t.Fatalf("Expected success enabling JetStream, got: %s", enableResponse)
+	}
+
+	usageResponse := goStringFree(GetAccountJetStreamUsage(accountCStr))
+	if isErrorResponse(usageResponse) {
+		t.Fatalf("Expected success getting JetStream usage, got: %s", usageResponse)
+	}
+
+	disableResponse := goStringFree(DisableAccountJetStream(accountCStr))
+	if isErrorResponse(disableResponse) {
+		t.Fatalf("Expected success disabling JetStream, got: %s", disableResponse)
+	}
+}
+
+// Test SetAccountJetStreamDomain records a domain that GetAccountJetStreamUsage
+// then surfaces back.
+func TestSetAccountJetStreamDomain_SurfacedInUsage(t *testing.T) {
+	port := 14503
+	srv := startJetStreamTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	srv.RegisterAccount("TENANT_D")
+
+	accountCStr := cString("TENANT_D")
+	defer cFree(accountCStr)
+	domainCStr := cString("tenant-d-domain")
+	defer cFree(domainCStr)
+
+	setResponse := goStringFree(SetAccountJetStreamDomain(accountCStr, domainCStr))
+	if isErrorResponse(setResponse) {
+		t.Fatalf("Expected success setting JetStream domain, got: %s", setResponse)
+	}
+
+	usageResponse := goStringFree(GetAccountJetStreamUsage(accountCStr))
+	if isErrorResponse(usageResponse) {
+		t.Fatalf("Expected success getting JetStream usage, got: %s", usageResponse)
+	}
+
+	var usage map[string]interface{}
+	if err := json.Unmarshal([]byte(usageResponse), &usage); err != nil {
+		t.Fatalf("Expected JSON usage, got %q: %v", usageResponse, err)
+	}
+	if usage["domain"] != "tenant-d-domain" {
+		t.Errorf("Expected domain 'tenant-d-domain' in usage, got: %v", usage["domain"])
+	}
+}
+
+// Test the account JetStream functions fail cleanly for an unknown account.
+func TestSetAccountJetStreamLimits_UnknownAccount(t *testing.T) {
+	port := 14504
+	srv := startJetStreamTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	accountCStr := cString("DOES_NOT_EXIST")
+	defer cFree(accountCStr)
+	limitsCStr := cString(`{"max_streams":1}`)
+	defer cFree(limitsCStr)
+
+	response := goStringFree(SetAccountJetStreamLimits(accountCStr, limitsCStr))
+
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error setting JetStream limits on an unknown account")
+	}
+}
@@ -0,0 +1,309 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"unsafe"
+
+	"github.com/nats-io/nats.go"
+)
+
+// jsContext opens a short-lived client connection to the current server
+// and returns its JetStream context. Callers must close the connection
+// (via nc.Close()) once they're done with it.
+func jsContext() (*nats.Conn, nats.JetStreamContext, error) {
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+
+	if !exists || srv == nil {
+		return nil, nil, fmt.Errorf("server not running")
+	}
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		nc.Close()
+		return nil, nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	return nc, js, nil
+}
+
+// JSCreateStream creates a JetStream stream from a JSON-encoded
+// nats.StreamConfig and returns the resulting nats.StreamInfo as JSON.
+//
+//export JSCreateStream
+func JSCreateStream(configJson *C.char) *C.char {
+	if configJson == nil {
+		return C.CString("ERROR: configuration cannot be null")
+	}
+
+	var cfg nats.StreamConfig
+	if err := json.Unmarshal([]byte(C.GoString(configJson)), &cfg); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse stream configuration: %v", err))
+	}
+
+	nc, js, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	info, err := js.AddStream(&cfg)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to create stream: %v", err))
+	}
+
+	jsonBytes, err := json.Marshal(info)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal stream info: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// JSUpdateStream updates an existing stream from a JSON-encoded
+// nats.StreamConfig and returns the resulting nats.StreamInfo as JSON.
+//
+//export JSUpdateStream
+func JSUpdateStream(configJson *C.char) *C.char {
+	if configJson == nil {
+		return C.CString("ERROR: configuration cannot be null")
+	}
+
+	var cfg nats.StreamConfig
+	if err := json.Unmarshal([]byte(C.GoString(configJson)), &cfg); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse stream configuration: %v", err))
+	}
+
+	nc, js, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	info, err := js.UpdateStream(&cfg)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to update stream: %v", err))
+	}
+
+	jsonBytes, err := json.Marshal(info)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal stream info: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// JSDeleteStream deletes the named stream.
+//
+//export JSDeleteStream
+func JSDeleteStream(name *C.char) *C.char {
+	if name == nil {
+		return C.CString("ERROR: stream name cannot be null")
+	}
+
+	nc, js, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	if err := js.DeleteStream(C.GoString(name)); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to delete stream: %v", err))
+	}
+
+	return C.CString("OK")
+}
+
+// JSListStreams returns nats.StreamInfo for every stream on the server, as
+// a JSON array.
+//
+//export JSListStreams
+func JSListStreams() *C.char {
+	nc, js, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	infos := make([]*nats.StreamInfo, 0)
+	for info := range js.StreamsInfo() {
+		infos = append(infos, info)
+	}
+
+	jsonBytes, err := json.Marshal(infos)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal stream list: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// JSStreamInfo returns the nats.StreamInfo for the named stream as JSON.
+//
+//export JSStreamInfo
+func JSStreamInfo(name *C.char) *C.char {
+	if name == nil {
+		return C.CString("ERROR: stream name cannot be null")
+	}
+
+	nc, js, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	info, err := js.StreamInfo(C.GoString(name))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to get stream info: %v", err))
+	}
+
+	jsonBytes, err := json.Marshal(info)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal stream info: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// JSCreateConsumer creates a consumer on the named stream from a
+// JSON-encoded nats.ConsumerConfig and returns the resulting
+// nats.ConsumerInfo as JSON.
+//
+//export JSCreateConsumer
+func JSCreateConsumer(stream *C.char, configJson *C.char) *C.char {
+	if stream == nil || configJson == nil {
+		return C.CString("ERROR: stream and configuration cannot be null")
+	}
+
+	var cfg nats.ConsumerConfig
+	if err := json.Unmarshal([]byte(C.GoString(configJson)), &cfg); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse consumer configuration: %v", err))
+	}
+
+	nc, js, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	info, err := js.AddConsumer(C.GoString(stream), &cfg)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to create consumer: %v", err))
+	}
+
+	jsonBytes, err := json.Marshal(info)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal consumer info: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// JSDeleteConsumer deletes the named consumer from the named stream.
+//
+//export JSDeleteConsumer
+func JSDeleteConsumer(stream *C.char, consumer *C.char) *C.char {
+	if stream == nil || consumer == nil {
+		return C.CString("ERROR: stream and consumer cannot be null")
+	}
+
+	nc, js, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	if err := js.DeleteConsumer(C.GoString(stream), C.GoString(consumer)); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to delete consumer: %v", err))
+	}
+
+	return C.CString("OK")
+}
+
+// JSPublish publishes a message to subject through JetStream and returns
+// the resulting nats.PubAck as JSON.
+//
+//export JSPublish
+func JSPublish(subject *C.char, payload *C.char, length C.int) *C.char {
+	if subject == nil {
+		return C.CString("ERROR: subject cannot be null")
+	}
+
+	var data []byte
+	if payload != nil && length > 0 {
+		data = C.GoBytes(unsafe.Pointer(payload), length)
+	}
+
+	nc, js, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	ack, err := js.Publish(C.GoString(subject), data)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to publish: %v", err))
+	}
+
+	jsonBytes, err := json.Marshal(ack)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal publish ack: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// JSPurgeOptions mirrors the subset of JetStream's purge request fields
+// exposed through JSPurgeStream's JSON options argument.
+type JSPurgeOptions struct {
+	Subject  string `json:"subject,omitempty"`
+	Sequence uint64 `json:"seq,omitempty"`
+	Keep     uint64 `json:"keep,omitempty"`
+}
+
+// JSPurgeStream purges messages from the named stream, optionally scoped
+// by a JSON-encoded JSPurgeOptions.
+//
+//export JSPurgeStream
+func JSPurgeStream(name *C.char, optsJson *C.char) *C.char {
+	if name == nil {
+		return C.CString("ERROR: stream name cannot be null")
+	}
+
+	var opts JSPurgeOptions
+	if optsJson != nil {
+		if optsStr := C.GoString(optsJson); optsStr != "" {
+			if err := json.Unmarshal([]byte(optsStr), &opts); err != nil {
+				return C.CString(fmt.Sprintf("ERROR: Failed to parse purge options: %v", err))
+			}
+		}
+	}
+
+	nc, js, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	var purgeOpts []nats.JSOpt
+	if opts.Subject != "" {
+		purgeOpts = append(purgeOpts, nats.PurgeSubject(opts.Subject))
+	}
+	if opts.Sequence > 0 {
+		purgeOpts = append(purgeOpts, nats.PurgeSequence(opts.Sequence))
+	}
+	if opts.Keep > 0 {
+		purgeOpts = append(purgeOpts, nats.PurgeKeep(opts.Keep))
+	}
+
+	if err := js.PurgeStream(C.GoString(name), purgeOpts...); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to purge stream: %v", err))
+	}
+
+	return C.CString("OK")
+}
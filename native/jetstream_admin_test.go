@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// startJetStreamTestServer mirrors TestIsJetStreamEnabled_WithJetStream's
+// setup: a server with JetStream enabled, registered as current.
+func startJetStreamTestServer(t *testing.T, port int) *server.Server {
+	opts := &server.Options{
+		Host:      "127.0.0.1",
+		Port:      port,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	}
+
+	srv, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatalf("Failed to create NATS server: %v", err)
+	}
+
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("Server did not become ready in time")
+	}
+
+	serverMu.Lock()
+	natsServers[port] = srv
+	natsServerOpts[port] = opts
+	setCurrentPortLocked(port)
+	publishFastState(port, srv, opts.JetStream)
+	serverMu.Unlock()
+
+	return srv
+}
+
+// Test the full stream/consumer lifecycle: create a stream and consumer,
+// publish, and verify info/list output.
+func TestJSCreateStream_PublishAndInfo(t *testing.T) {
+	port := 14340
+	srv := startJetStreamTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	streamCfg := cString(`{"name":"ORDERS","subjects":["orders.>"]}`)
+	defer cFree(streamCfg)
+
+	createResponse := goStringFree(JSCreateStream(streamCfg))
+
+	if isErrorResponse(createResponse) {
+		t.Fatalf("Expected success creating stream, got: %s", createResponse)
+	}
+
+	var streamInfo nats.StreamInfo
+	if err := json.Unmarshal([]byte(createResponse), &streamInfo); err != nil {
+		t.Fatalf("Expected JSON stream info, got %q: %v", createResponse, err)
+	}
+	if streamInfo.Config.Name != "ORDERS" {
+		t.Errorf("Expected stream name 'ORDERS', got: %s", streamInfo.Config.Name)
+	}
+
+	consumerCfg := cString(`{"durable_name":"processor"}`)
+	defer cFree(consumerCfg)
+	streamName := cString("ORDERS")
+	defer cFree(streamName)
+
+	consumerResponse := goStringFree(JSCreateConsumer(streamName, consumerCfg))
+
+	if isErrorResponse(consumerResponse) {
+		t.Fatalf("Expected success creating consumer, got: %s", consumerResponse)
+	}
+
+	subject := cString("orders.created")
+	defer cFree(subject)
+	payload := cString(`{"id":1}`)
+	defer cFree(payload)
+
+	publishResponse := goStringFree(JSPublish(subject, payload, cInt(len(`{"id":1}`))))
+
+	if isErrorResponse(publishResponse) {
+		t.Fatalf("Expected success publishing, got: %s", publishResponse)
+	}
+
+	var ack nats.PubAck
+	if err := json.Unmarshal([]byte(publishResponse), &ack); err != nil {
+		t.Fatalf("Expected JSON publish ack, got %q: %v", publishResponse, err)
+	}
+	if ack.Stream != "ORDERS" {
+		t.Errorf("Expected ack for stream 'ORDERS', got: %s", ack.Stream)
+	}
+
+	infoResponse := goStringFree(JSStreamInfo(streamName))
+
+	if isErrorResponse(infoResponse) {
+		t.Fatalf("Expected success getting stream info, got: %s", infoResponse)
+	}
+	if err := json.Unmarshal([]byte(infoResponse), &streamInfo); err != nil {
+		t.Fatalf("Expected JSON stream info, got %q: %v", infoResponse, err)
+	}
+	if streamInfo.State.Msgs != 1 {
+		t.Errorf("Expected 1 message in stream, got: %d", streamInfo.State.Msgs)
+	}
+
+	listResponse := goStringFree(JSListStreams())
+
+	var streams []nats.StreamInfo
+	if err := json.Unmarshal([]byte(listResponse), &streams); err != nil {
+		t.Fatalf("Expected JSON stream list, got %q: %v", listResponse, err)
+	}
+	if len(streams) != 1 {
+		t.Errorf("Expected 1 stream, got: %d", len(streams))
+	}
+
+	consumerName := cString("processor")
+	defer cFree(consumerName)
+	deleteConsumerResult := JSDeleteConsumer(streamName, consumerName)
+	cFree(deleteConsumerResult)
+
+	purgeResponse := goStringFree(JSPurgeStream(streamName, nil))
+	if isErrorResponse(purgeResponse) {
+		t.Fatalf("Expected success purging stream, got: %s", purgeResponse)
+	}
+
+	deleteResponse := goStringFree(JSDeleteStream(streamName))
+	if isErrorResponse(deleteResponse) {
+		t.Fatalf("Expected success deleting stream, got: %s", deleteResponse)
+	}
+}
+
+// Test that the JetStream admin functions fail cleanly when no server is
+// running.
+func TestJSCreateStream_ServerNotRunning(t *testing.T) {
+	serverMu.Lock()
+	setCurrentPortLocked(99998)
+	serverMu.Unlock()
+
+	streamCfg := cString(`{"name":"ORDERS","subjects":["orders.>"]}`)
+	defer cFree(streamCfg)
+
+	response := goStringFree(JSCreateStream(streamCfg))
+
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error when server not running")
+	}
+}
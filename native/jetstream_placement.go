@@ -0,0 +1,354 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// jsAdminRequestTimeout bounds raw system-API requests like the peer
+// eviction call in MoveStream, mirroring PushAccountJWT/DeleteAccountJWT's
+// 5-second timeout in jwt_lifecycle.go.
+const jsAdminRequestTimeout = 5 * time.Second
+
+// PlacementConfig is the JSON shape CreateStreamWithPlacement/MoveStream
+// accept for a stream's Placement block. Cluster/Tags mirror nats.go's
+// nats.Placement directly; Preferred and AllowOverflow are hints this shim
+// applies itself, since the server's own Placement has no notion of either.
+type PlacementConfig struct {
+	Cluster       string   `json:"cluster"`
+	Tags          []string `json:"tags"`
+	Preferred     string   `json:"preferred"`
+	AllowOverflow bool     `json:"allow_overflow"`
+}
+
+// StreamPlacementConfig is a nats.StreamConfig plus our richer Placement
+// block, so callers send one JSON document instead of two.
+type StreamPlacementConfig struct {
+	nats.StreamConfig
+	Placement PlacementConfig `json:"placement"`
+}
+
+// placementResult mirrors the subset of nats.StreamInfo.Cluster that tells
+// a caller which peers a stream actually landed on, so it can verify
+// placement instead of trusting the request was honored blindly.
+type placementResult struct {
+	Stream  *nats.StreamInfo `json:"stream"`
+	Cluster string           `json:"cluster"`
+	Leader  string           `json:"leader"`
+	Peers   []string         `json:"peers"`
+}
+
+func placementResultFor(info *nats.StreamInfo) placementResult {
+	result := placementResult{Stream: info}
+	if info.Cluster == nil {
+		return result
+	}
+	result.Cluster = info.Cluster.Name
+	result.Leader = info.Cluster.Leader
+	for _, replica := range info.Cluster.Replicas {
+		result.Peers = append(result.Peers, replica.Name)
+	}
+	return result
+}
+
+// currentServer returns the running instance for the current port, the
+// same lookup jsContext does before opening a client connection, for the
+// callers below that need the *server.Server itself (to call Jsz or Warnf)
+// rather than a client-side JetStream context.
+func currentServer() (*server.Server, error) {
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+
+	if !exists || srv == nil {
+		return nil, fmt.Errorf("server not running")
+	}
+	return srv, nil
+}
+
+// peerStreamCounts tallies, for every peer srv's own Jsz can see hosting a
+// stream (as leader or replica) across every account, how many streams it
+// currently carries. Jsz only reports cluster membership through each
+// stream's own replica list - it has no API for a remote peer's storage
+// usage or limits - so this is the one concrete, comparable signal
+// available locally for ranking candidate peers.
+func peerStreamCounts(srv *server.Server) (map[string]int, error) {
+	jsi, err := srv.Jsz(&server.JSzOptions{Accounts: true, Streams: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Jsz for placement scoring: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, acctDetail := range jsi.AccountDetails {
+		for _, stream := range acctDetail.Streams {
+			if stream.Cluster == nil {
+				continue
+			}
+			if stream.Cluster.Leader != "" {
+				counts[stream.Cluster.Leader]++
+			}
+			for _, replica := range stream.Cluster.Replicas {
+				counts[replica.Name]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// leastLoadedCandidate returns whichever of candidates Jsz reports carrying
+// the fewest streams, breaking ties by keeping the first one seen. A
+// candidate Jsz has never seen hosting any stream counts as zero load.
+func leastLoadedCandidate(counts map[string]int, candidates []string) string {
+	best := ""
+	bestCount := -1
+	for _, candidate := range candidates {
+		n := counts[candidate]
+		if bestCount == -1 || n < bestCount {
+			best = candidate
+			bestCount = n
+		}
+	}
+	return best
+}
+
+// hasStorageHeadroom reports whether srv's own JetStream storage usage is
+// still under its configured limit. Jsz can't tell us a remote peer's
+// storage usage, so this is necessarily a same-node proxy: it only
+// protects against retrying placement onto a cluster that is, as far as
+// this node can see, already globally full.
+func hasStorageHeadroom(srv *server.Server) bool {
+	jsi, err := srv.Jsz(nil)
+	if err != nil || jsi == nil {
+		return true
+	}
+	if jsi.Config.MaxStore <= 0 {
+		// No configured limit (or unlimited) means there's nothing to run out of.
+		return true
+	}
+	return jsi.JetStreamStats.Store < uint64(jsi.Config.MaxStore)
+}
+
+// CreateStreamWithPlacement creates a stream honoring the requested tag/
+// cluster placement and, when AllowOverflow is set and the strict request
+// can't be satisfied, retries once with the cluster constraint relaxed to
+// whichever tag-matching peer Jsz reports as least loaded (by current
+// stream count) and with spare storage headroom, rather than leaving the
+// meta-leader to pick blind among every tag-matching peer in every
+// cluster. If Jsz scoring can't identify a preferred peer (no tags were
+// given to narrow the candidates, or no headroom is left anywhere this
+// node can see), the retry falls back to the old behavior of simply
+// dropping the cluster constraint.
+//
+//export CreateStreamWithPlacement
+func CreateStreamWithPlacement(streamJson *C.char) *C.char {
+	if streamJson == nil {
+		return C.CString("ERROR: configuration cannot be null")
+	}
+
+	var cfg StreamPlacementConfig
+	if err := json.Unmarshal([]byte(C.GoString(streamJson)), &cfg); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse stream configuration: %v", err))
+	}
+
+	if cfg.Placement.Cluster != "" || len(cfg.Placement.Tags) > 0 {
+		cfg.StreamConfig.Placement = &nats.Placement{
+			Cluster: cfg.Placement.Cluster,
+			Tags:    cfg.Placement.Tags,
+		}
+	}
+
+	nc, js, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	info, err := js.AddStream(&cfg.StreamConfig)
+	if err != nil && cfg.Placement.AllowOverflow && cfg.Placement.Cluster != "" {
+		relaxed := &nats.Placement{Tags: cfg.Placement.Tags}
+
+		if srv, srvErr := currentServer(); srvErr == nil && hasStorageHeadroom(srv) && len(cfg.Placement.Tags) > 0 {
+			if counts, scoreErr := peerStreamCounts(srv); scoreErr == nil {
+				if peer := leastLoadedCandidate(counts, cfg.Placement.Tags); peer != "" {
+					relaxed.Tags = []string{peer}
+				}
+			}
+		}
+
+		cfg.StreamConfig.Placement = relaxed
+		info, err = js.AddStream(&cfg.StreamConfig)
+	}
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to create stream: %v", err))
+	}
+
+	jsonBytes, err := json.Marshal(placementResultFor(info))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal placement result: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// TagServer adds tags to the current server's options and reloads it, so
+// the JetStream meta-leader sees the new tags on its next peer refresh
+// without requiring a restart.
+//
+//export TagServer
+func TagServer(tagsJson *C.char) *C.char {
+	if tagsJson == nil {
+		return C.CString("ERROR: tags cannot be null")
+	}
+
+	var tags []string
+	if err := json.Unmarshal([]byte(C.GoString(tagsJson)), &tags); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse tags: %v", err))
+	}
+
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, opts, err := currentOptsLocked()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	merged := append([]string{}, opts.Tags...)
+	for _, tag := range tags {
+		if !containsTag(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	opts.Tags = jwt.TagList(merged)
+
+	if err := srv.ReloadOptions(opts); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to reload options: %v", err))
+	}
+
+	return C.CString("OK")
+}
+
+// containsTag reports whether tag is present in tags.
+func containsTag(tags []string, tag string) bool {
+	for _, existing := range tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// UntagServer removes tags from the current server's options and reloads
+// it, the inverse of TagServer.
+//
+//export UntagServer
+func UntagServer(tagsJson *C.char) *C.char {
+	if tagsJson == nil {
+		return C.CString("ERROR: tags cannot be null")
+	}
+
+	var tags []string
+	if err := json.Unmarshal([]byte(C.GoString(tagsJson)), &tags); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse tags: %v", err))
+	}
+
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, opts, err := currentOptsLocked()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	remaining := make([]string, 0, len(opts.Tags))
+	for _, existing := range opts.Tags {
+		if !containsTag(tags, existing) {
+			remaining = append(remaining, existing)
+		}
+	}
+	opts.Tags = jwt.TagList(remaining)
+
+	if err := srv.ReloadOptions(opts); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to reload options: %v", err))
+	}
+
+	return C.CString("OK")
+}
+
+// movePlacementRequest is MoveStream's JSON argument: the new placement to
+// update the stream's config with, plus the set of current replica peer
+// names the caller wants evicted (StreamInfo's Cluster.Replicas tells a
+// caller which peers are current, but not which cluster each one belongs
+// to, so this shim cannot derive "no longer matching" on its own).
+type movePlacementRequest struct {
+	Placement  PlacementConfig `json:"placement"`
+	EvictPeers []string        `json:"evict_peers"`
+}
+
+// MoveStream re-points the named stream at a new placement and issues
+// $JS.API.STREAM.PEER.REMOVE for each peer in EvictPeers, letting the
+// meta-leader replace them with peers that satisfy the new placement.
+//
+//export MoveStream
+func MoveStream(stream *C.char, moveJson *C.char) *C.char {
+	if stream == nil || moveJson == nil {
+		return C.CString("ERROR: stream and move request cannot be null")
+	}
+
+	var move movePlacementRequest
+	if err := json.Unmarshal([]byte(C.GoString(moveJson)), &move); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse move request: %v", err))
+	}
+
+	streamName := C.GoString(stream)
+
+	nc, js, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	info, err := js.StreamInfo(streamName)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to get stream info: %v", err))
+	}
+
+	cfg := info.Config
+	cfg.Placement = &nats.Placement{Cluster: move.Placement.Cluster, Tags: move.Placement.Tags}
+
+	updated, err := js.UpdateStream(&cfg)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to update stream placement: %v", err))
+	}
+
+	// Best-effort: the meta-leader processes these asynchronously, and a
+	// peer that was already replaced by the UpdateStream above will simply
+	// reject the removal. The placement change itself already succeeded by
+	// this point, so a rejected eviction is logged and skipped rather than
+	// turned into an ERROR that would discard that success.
+	srv, srvErr := currentServer()
+	for _, peer := range move.EvictPeers {
+		subject := fmt.Sprintf("$JS.API.STREAM.PEER.REMOVE.%s", streamName)
+		payload, err := json.Marshal(map[string]string{"peer": peer})
+		if err != nil {
+			continue
+		}
+		if _, err := nc.Request(subject, payload, jsAdminRequestTimeout); err != nil && srvErr == nil {
+			srv.Warnf("MoveStream: failed to evict peer %q for stream %q: %v", peer, streamName, err)
+		}
+	}
+
+	jsonBytes, err := json.Marshal(placementResultFor(updated))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal placement result: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// Test CreateStreamWithPlacement on a single-node server: no cluster/tag
+// constraint is satisfiable on a single node, so placement is reported
+// without a cluster name, confirming the request round-trips correctly.
+func TestCreateStreamWithPlacement_SingleNode(t *testing.T) {
+	port := 14370
+	srv := startJetStreamTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	streamCfg := cString(`{"name":"PLACED","subjects":["placed.>"],"placement":{"tags":["region:us-east"],"allow_overflow":true}}`)
+	defer cFree(streamCfg)
+
+	response := goStringFree(CreateStreamWithPlacement(streamCfg))
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success creating stream with placement, got: %s", response)
+	}
+
+	var parsed struct {
+		Stream struct {
+			Config struct {
+				Name string `json:"name"`
+			} `json:"config"`
+		} `json:"stream"`
+	}
+	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
+		t.Fatalf("Failed to parse placement response: %v", err)
+	}
+	if parsed.Stream.Config.Name != "PLACED" {
+		t.Errorf("Expected stream name 'PLACED', got: %s", parsed.Stream.Config.Name)
+	}
+}
+
+// Test TagServer/UntagServer mutate opts.Tags and reload cleanly.
+func TestTagServer_AddAndRemove(t *testing.T) {
+	port := 14371
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	serverMu.Lock()
+	natsServerOpts[port] = &server.Options{Host: "127.0.0.1", Port: port}
+	serverMu.Unlock()
+
+	addTags := cString(`["region:us-east","tier:fast"]`)
+	defer cFree(addTags)
+
+	addResponse := goStringFree(TagServer(addTags))
+	if isErrorResponse(addResponse) {
+		t.Fatalf("Expected success tagging server, got: %s", addResponse)
+	}
+
+	serverMu.Lock()
+	gotTags := append([]string{}, natsServerOpts[port].Tags...)
+	serverMu.Unlock()
+	if !containsTag(gotTags, "region:us-east") || !containsTag(gotTags, "tier:fast") {
+		t.Errorf("Expected both tags present after TagServer, got: %v", gotTags)
+	}
+
+	removeTags := cString(`["tier:fast"]`)
+	defer cFree(removeTags)
+
+	removeResponse := goStringFree(UntagServer(removeTags))
+	if isErrorResponse(removeResponse) {
+		t.Fatalf("Expected success untagging server, got: %s", removeResponse)
+	}
+
+	serverMu.Lock()
+	gotTags = append([]string{}, natsServerOpts[port].Tags...)
+	serverMu.Unlock()
+	if containsTag(gotTags, "tier:fast") {
+		t.Error("Expected 'tier:fast' to be removed after UntagServer")
+	}
+	if !containsTag(gotTags, "region:us-east") {
+		t.Error("Expected 'region:us-east' to remain after UntagServer")
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+)
+
+// keyPairResponse is the {jwt, seed, pub} shape returned by every identity-
+// minting function below, so callers always unpack the same three fields
+// regardless of whether they created an operator, account, or user.
+type keyPairResponse struct {
+	JWT  string `json:"jwt"`
+	Seed string `json:"seed"`
+	Pub  string `json:"pub"`
+}
+
+// CreateOperator mints a fresh operator identity and, when systemAccount is
+// non-empty, a system account signed by it - the minimum trust chain an
+// embedded deployment needs before RegisterAccountJWT/SetOperatorTrust have
+// anything to verify against.
+//
+//export CreateOperator
+func CreateOperator(name *C.char, systemAccount *C.char) *C.char {
+	operatorKP, err := nkeys.CreateOperator()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to create operator key: %v", err))
+	}
+	operatorPub, err := operatorKP.PublicKey()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to derive operator public key: %v", err))
+	}
+	operatorSeed, err := operatorKP.Seed()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to derive operator seed: %v", err))
+	}
+
+	operatorClaims := jwt.NewOperatorClaims(operatorPub)
+	if name != nil {
+		operatorClaims.Name = C.GoString(name)
+	}
+
+	response := map[string]interface{}{}
+
+	sysAcctName := ""
+	if systemAccount != nil {
+		sysAcctName = C.GoString(systemAccount)
+	}
+
+	if sysAcctName != "" {
+		sysKP, err := nkeys.CreateAccount()
+		if err != nil {
+			return C.CString(fmt.Sprintf("ERROR: Failed to create system account key: %v", err))
+		}
+		sysPub, err := sysKP.PublicKey()
+		if err != nil {
+			return C.CString(fmt.Sprintf("ERROR: Failed to derive system account public key: %v", err))
+		}
+		sysSeed, err := sysKP.Seed()
+		if err != nil {
+			return C.CString(fmt.Sprintf("ERROR: Failed to derive system account seed: %v", err))
+		}
+
+		sysClaims := jwt.NewAccountClaims(sysPub)
+		sysClaims.Name = sysAcctName
+		operatorClaims.SystemAccount = sysPub
+
+		sysJWT, err := sysClaims.Encode(operatorKP)
+		if err != nil {
+			return C.CString(fmt.Sprintf("ERROR: Failed to encode system account JWT: %v", err))
+		}
+
+		accountProvisioningMu.Lock()
+		lastAccountClaims[sysPub] = sysClaims
+		accountProvisioningMu.Unlock()
+
+		response["system_account"] = keyPairResponse{JWT: sysJWT, Seed: string(sysSeed), Pub: sysPub}
+	}
+
+	operatorJWT, err := operatorClaims.Encode(operatorKP)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to encode operator JWT: %v", err))
+	}
+
+	response["jwt"] = operatorJWT
+	response["seed"] = string(operatorSeed)
+	response["pub"] = operatorPub
+
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal operator info: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// UserConfig is the JSON shape CreateUserWithJWT accepts to describe the
+// user being provisioned, mirroring AccountConfig's role for accounts.
+type UserConfig struct {
+	Name             string   `json:"name"`
+	AllowedPublish   []string `json:"allowed_publish"`
+	AllowedSubscribe []string `json:"allowed_subscribe"`
+}
+
+// userCredsResponse extends keyPairResponse with the ready-to-use .creds
+// file body, since that - not the bare JWT/seed pair - is what a connecting
+// client actually needs.
+type userCredsResponse struct {
+	JWT   string `json:"jwt"`
+	Seed  string `json:"seed"`
+	Pub   string `json:"pub"`
+	Creds string `json:"creds"`
+}
+
+// CreateUserWithJWT mints a user identity signed by the account identified
+// by accountSeed and returns it alongside a .creds file body suitable for
+// handing straight to a nats.go client via nats.UserCredentials.
+//
+//export CreateUserWithJWT
+func CreateUserWithJWT(accountSeed *C.char, userConfigJson *C.char) *C.char {
+	if accountSeed == nil {
+		return C.CString("ERROR: account seed cannot be null")
+	}
+
+	accountKP, err := nkeys.FromSeed([]byte(C.GoString(accountSeed)))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Invalid account seed: %v", err))
+	}
+	accountPub, err := accountKP.PublicKey()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to derive account public key: %v", err))
+	}
+
+	var userCfg UserConfig
+	if userConfigJson != nil {
+		if cfgStr := C.GoString(userConfigJson); cfgStr != "" {
+			if err := json.Unmarshal([]byte(cfgStr), &userCfg); err != nil {
+				return C.CString(fmt.Sprintf("ERROR: Failed to parse user configuration: %v", err))
+			}
+		}
+	}
+
+	userKP, err := nkeys.CreateUser()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to create user key: %v", err))
+	}
+	userPub, err := userKP.PublicKey()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to derive user public key: %v", err))
+	}
+	userSeed, err := userKP.Seed()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to derive user seed: %v", err))
+	}
+
+	userClaims := jwt.NewUserClaims(userPub)
+	userClaims.Name = userCfg.Name
+	userClaims.Pub.Allow.Add(userCfg.AllowedPublish...)
+	userClaims.Sub.Allow.Add(userCfg.AllowedSubscribe...)
+	userClaims.IssuerAccount = accountPub
+
+	userJWT, err := userClaims.Encode(accountKP)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to encode user JWT: %v", err))
+	}
+
+	credsBytes, err := jwt.FormatUserConfig(userJWT, userSeed)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to format user credentials: %v", err))
+	}
+
+	response := userCredsResponse{
+		JWT:   userJWT,
+		Seed:  string(userSeed),
+		Pub:   userPub,
+		Creds: string(credsBytes),
+	}
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal user credentials: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// signingKeyResponse is returned by AddAccountSigningKey/RotateAccountSigningKey
+// alongside the account JWT re-signed to include the change.
+type signingKeyResponse struct {
+	JWT            string `json:"jwt"`
+	SigningKeyPub  string `json:"signing_key_pub"`
+	SigningKeySeed string `json:"signing_key_seed"`
+}
+
+// addSigningKeyLocked creates a new account signing keypair, adds it to
+// claims (fetching the account's last-known claims from lastAccountClaims
+// if none are passed in), and re-encodes the account JWT with accountKP.
+// Caller must hold accountProvisioningMu.
+func addSigningKeyLocked(accountKP nkeys.KeyPair, accountPub string) (signingKeyResponse, error) {
+	claims, exists := lastAccountClaims[accountPub]
+	if !exists {
+		claims = jwt.NewAccountClaims(accountPub)
+	}
+
+	signingKP, err := nkeys.CreateAccount()
+	if err != nil {
+		return signingKeyResponse{}, fmt.Errorf("failed to create signing key: %w", err)
+	}
+	signingPub, err := signingKP.PublicKey()
+	if err != nil {
+		return signingKeyResponse{}, fmt.Errorf("failed to derive signing public key: %w", err)
+	}
+	signingSeed, err := signingKP.Seed()
+	if err != nil {
+		return signingKeyResponse{}, fmt.Errorf("failed to derive signing seed: %w", err)
+	}
+
+	claims.SigningKeys.Add(signingPub)
+
+	newJWT, err := claims.Encode(accountKP)
+	if err != nil {
+		return signingKeyResponse{}, fmt.Errorf("failed to encode account JWT: %w", err)
+	}
+
+	lastAccountClaims[accountPub] = claims
+
+	return signingKeyResponse{
+		JWT:            newJWT,
+		SigningKeyPub:  signingPub,
+		SigningKeySeed: string(signingSeed),
+	}, nil
+}
+
+// AddAccountSigningKey adds a new signing key to the account identified by
+// accountSeed, so that account can delegate user JWT signing without
+// handing out its main identity key, and returns the re-signed account JWT.
+//
+//export AddAccountSigningKey
+func AddAccountSigningKey(accountSeed *C.char) *C.char {
+	if accountSeed == nil {
+		return C.CString("ERROR: account seed cannot be null")
+	}
+
+	accountKP, err := nkeys.FromSeed([]byte(C.GoString(accountSeed)))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Invalid account seed: %v", err))
+	}
+	accountPub, err := accountKP.PublicKey()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to derive account public key: %v", err))
+	}
+
+	accountProvisioningMu.Lock()
+	response, err := addSigningKeyLocked(accountKP, accountPub)
+	accountProvisioningMu.Unlock()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal signing key info: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// RotateAccountSigningKey retires oldSigningKeyPub and adds a replacement
+// signing key in the same JWT update, so a compromised or expiring signing
+// key never has a window where both the old and new key are simultaneously
+// trusted.
+//
+//export RotateAccountSigningKey
+func RotateAccountSigningKey(accountSeed *C.char, oldSigningKeyPub *C.char) *C.char {
+	if accountSeed == nil || oldSigningKeyPub == nil {
+		return C.CString("ERROR: account seed and old signing key cannot be null")
+	}
+
+	accountKP, err := nkeys.FromSeed([]byte(C.GoString(accountSeed)))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Invalid account seed: %v", err))
+	}
+	accountPub, err := accountKP.PublicKey()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to derive account public key: %v", err))
+	}
+
+	accountProvisioningMu.Lock()
+	defer accountProvisioningMu.Unlock()
+
+	claims, exists := lastAccountClaims[accountPub]
+	if !exists {
+		return C.CString("ERROR: No claims on file for account; register it via RegisterAccountJWT first")
+	}
+	claims.SigningKeys.Remove(C.GoString(oldSigningKeyPub))
+	lastAccountClaims[accountPub] = claims
+
+	response, err := addSigningKeyLocked(accountKP, accountPub)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal signing key info: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// systemClient opens a short-lived client connection to the current server
+// for talking to the system account's $SYS.REQ.CLAIMS API, analogous to
+// jsContext's role for JetStream admin calls in jetstream_admin.go.
+func systemClient() (*nats.Conn, error) {
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+
+	if !exists || srv == nil {
+		return nil, fmt.Errorf("server not running")
+	}
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to server: %w", err)
+	}
+	return nc, nil
+}
+
+// PushAccountJWT publishes an account JWT to the configured account
+// resolver via $SYS.REQ.CLAIMS.UPDATE, the same mechanism `nsc push` uses
+// against a running server.
+//
+//export PushAccountJWT
+func PushAccountJWT(accountJwt *C.char) *C.char {
+	if accountJwt == nil {
+		return C.CString("ERROR: account JWT cannot be null")
+	}
+
+	nc, err := systemClient()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	reply, err := nc.Request("$SYS.REQ.CLAIMS.UPDATE", []byte(C.GoString(accountJwt)), 5*time.Second)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to push account JWT: %v", err))
+	}
+
+	return C.CString(string(reply.Data))
+}
+
+// DeleteAccountJWT removes an account's JWT from the configured account
+// resolver via $SYS.REQ.CLAIMS.DELETE, requiring the resolver to have been
+// configured with allow_delete (see ResolverConfig in nats-bindings.go).
+//
+//export DeleteAccountJWT
+func DeleteAccountJWT(pubKey *C.char) *C.char {
+	if pubKey == nil {
+		return C.CString("ERROR: account public key cannot be null")
+	}
+
+	nc, err := systemClient()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	reply, err := nc.Request("$SYS.REQ.CLAIMS.DELETE", []byte(C.GoString(pubKey)), 5*time.Second)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to delete account JWT: %v", err))
+	}
+
+	return C.CString(string(reply.Data))
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+)
+
+// Test CreateOperator mints a self-consistent operator identity, and that
+// requesting a system account produces one signed by that operator.
+func TestCreateOperator_WithSystemAccount(t *testing.T) {
+	name := cString("test-operator")
+	defer cFree(name)
+	sysAccount := cString("SYS")
+	defer cFree(sysAccount)
+
+	response := goStringFree(CreateOperator(name, sysAccount))
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success creating operator, got: %s", response)
+	}
+
+	var parsed struct {
+		JWT           string `json:"jwt"`
+		Pub           string `json:"pub"`
+		SystemAccount struct {
+			JWT string `json:"jwt"`
+			Pub string `json:"pub"`
+		} `json:"system_account"`
+	}
+	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
+		t.Fatalf("Failed to parse CreateOperator response: %v", err)
+	}
+
+	claims, err := jwt.DecodeOperatorClaims(parsed.JWT)
+	if err != nil {
+		t.Fatalf("Failed to decode operator JWT: %v", err)
+	}
+	if claims.Subject != parsed.Pub {
+		t.Errorf("Expected operator claims subject %q to match pub %q", claims.Subject, parsed.Pub)
+	}
+	if claims.SystemAccount != parsed.SystemAccount.Pub {
+		t.Errorf("Expected operator SystemAccount %q to match minted system account %q", claims.SystemAccount, parsed.SystemAccount.Pub)
+	}
+
+	sysClaims, err := jwt.DecodeAccountClaims(parsed.SystemAccount.JWT)
+	if err != nil {
+		t.Fatalf("Failed to decode system account JWT: %v", err)
+	}
+	if sysClaims.Issuer != parsed.Pub {
+		t.Errorf("Expected system account issuer %q to be the operator %q", sysClaims.Issuer, parsed.Pub)
+	}
+}
+
+// Test CreateUserWithJWT produces a user JWT signed by the given account
+// seed and a .creds body that embeds both the JWT and the seed.
+func TestCreateUserWithJWT_ProducesUsableCreds(t *testing.T) {
+	accountKP, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatalf("Failed to create account key: %v", err)
+	}
+	accountSeed, _ := accountKP.Seed()
+	accountPub, _ := accountKP.PublicKey()
+
+	seedCStr := cString(string(accountSeed))
+	defer cFree(seedCStr)
+	cfgCStr := cString(`{"name":"alice","allowed_publish":["orders.>"],"allowed_subscribe":["orders.>"]}`)
+	defer cFree(cfgCStr)
+
+	response := goStringFree(CreateUserWithJWT(seedCStr, cfgCStr))
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success creating user, got: %s", response)
+	}
+
+	var parsed struct {
+		JWT   string `json:"jwt"`
+		Pub   string `json:"pub"`
+		Creds string `json:"creds"`
+	}
+	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
+		t.Fatalf("Failed to parse CreateUserWithJWT response: %v", err)
+	}
+
+	userClaims, err := jwt.DecodeUserClaims(parsed.JWT)
+	if err != nil {
+		t.Fatalf("Failed to decode user JWT: %v", err)
+	}
+	if userClaims.Issuer != accountPub {
+		t.Errorf("Expected user JWT issuer %q to be the account %q", userClaims.Issuer, accountPub)
+	}
+	if userClaims.Name != "alice" {
+		t.Errorf("Expected user name 'alice', got: %s", userClaims.Name)
+	}
+
+	if len(parsed.Creds) == 0 {
+		t.Error("Expected non-empty .creds body")
+	}
+}
+
+// Test AddAccountSigningKey and RotateAccountSigningKey mutate the same
+// account's signing key set without touching its identity key.
+func TestAccountSigningKeyLifecycle(t *testing.T) {
+	accountKP, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatalf("Failed to create account key: %v", err)
+	}
+	accountSeed, _ := accountKP.Seed()
+
+	seedCStr := cString(string(accountSeed))
+	defer cFree(seedCStr)
+
+	addResponse := goStringFree(AddAccountSigningKey(seedCStr))
+	if isErrorResponse(addResponse) {
+		t.Fatalf("Expected success adding signing key, got: %s", addResponse)
+	}
+
+	var added struct {
+		SigningKeyPub string `json:"signing_key_pub"`
+	}
+	if err := json.Unmarshal([]byte(addResponse), &added); err != nil {
+		t.Fatalf("Failed to parse AddAccountSigningKey response: %v", err)
+	}
+
+	oldKeyCStr := cString(added.SigningKeyPub)
+	defer cFree(oldKeyCStr)
+
+	rotateResponse := goStringFree(RotateAccountSigningKey(seedCStr, oldKeyCStr))
+	if isErrorResponse(rotateResponse) {
+		t.Fatalf("Expected success rotating signing key, got: %s", rotateResponse)
+	}
+
+	var rotated struct {
+		JWT           string `json:"jwt"`
+		SigningKeyPub string `json:"signing_key_pub"`
+	}
+	if err := json.Unmarshal([]byte(rotateResponse), &rotated); err != nil {
+		t.Fatalf("Failed to parse RotateAccountSigningKey response: %v", err)
+	}
+
+	claims, err := jwt.DecodeAccountClaims(rotated.JWT)
+	if err != nil {
+		t.Fatalf("Failed to decode rotated account JWT: %v", err)
+	}
+	if !claims.SigningKeys.Contains(rotated.SigningKeyPub) {
+		t.Error("Expected rotated account JWT to contain the new signing key")
+	}
+	if claims.SigningKeys.Contains(added.SigningKeyPub) {
+		t.Error("Expected rotated account JWT to no longer contain the retired signing key")
+	}
+}
+
+// Test PushAccountJWT surfaces a clear error when no server is running,
+// rather than hanging or panicking on a nil connection.
+func TestPushAccountJWT_ServerNotRunning(t *testing.T) {
+	serverMu.Lock()
+	setCurrentPortLocked(0)
+	serverMu.Unlock()
+
+	jwtCStr := cString("not-a-real-jwt")
+	defer cFree(jwtCStr)
+
+	response := goStringFree(PushAccountJWT(jwtCStr))
+
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error pushing an account JWT with no server running")
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+/*
+#include <stdlib.h>
+
+typedef void (*monitoring_callback)(long long sub_id, char* json_patch);
+
+static inline void call_monitoring_callback(monitoring_callback cb, long long subID, char* patch) {
+    cb(subID, patch);
+}
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// fullSnapshotEvery is how many intervals elapse between forced full
+// snapshots, letting a UI recover from a missed diff without resubscribing.
+const fullSnapshotEvery = 10
+
+// monitoringPatchOp is a single RFC 6902 JSON Patch operation.
+type monitoringPatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type monitoringSubscription struct {
+	id       int64
+	kind     string
+	interval time.Duration
+	callback C.monitoring_callback
+	last     map[string]interface{}
+	tick     int
+	stop     chan struct{}
+}
+
+var (
+	monitoringSubsMu  sync.Mutex
+	monitoringSubs    = make(map[int64]*monitoringSubscription)
+	monitoringSubsSeq int64
+)
+
+// snapshotForKind fetches the current monitoring snapshot for kind as a
+// generic map, reusing the same server calls as the poll-per-call Get*z
+// functions.
+func snapshotForKind(kind string) (map[string]interface{}, error) {
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+
+	if !exists || srv == nil {
+		return nil, fmt.Errorf("server not running")
+	}
+
+	var (
+		payload interface{}
+		err     error
+	)
+
+	switch kind {
+	case "varz":
+		payload, err = srv.Varz(nil)
+	case "connz":
+		payload, err = srv.Connz(nil)
+	case "subsz":
+		payload, err = srv.Subsz(nil)
+	case "jsz":
+		payload, err = srv.Jsz(nil)
+	case "accountz":
+		payload, err = srv.Accountz(nil)
+	default:
+		return nil, fmt.Errorf("unknown monitoring kind %q", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// diffSnapshots produces the RFC 6902 patch that transforms prev into next,
+// comparing by key at the top level (and one level of nested maps) so that
+// unrelated fields don't generate noisy replace operations.
+func diffSnapshots(prev, next map[string]interface{}) []monitoringPatchOp {
+	var ops []monitoringPatchOp
+
+	for key, nextVal := range next {
+		prevVal, existed := prev[key]
+		if !existed {
+			ops = append(ops, monitoringPatchOp{Op: "add", Path: "/" + key, Value: nextVal})
+			continue
+		}
+		if !reflect.DeepEqual(prevVal, nextVal) {
+			ops = append(ops, monitoringPatchOp{Op: "replace", Path: "/" + key, Value: nextVal})
+		}
+	}
+	for key := range prev {
+		if _, exists := next[key]; !exists {
+			ops = append(ops, monitoringPatchOp{Op: "remove", Path: "/" + key})
+		}
+	}
+
+	return ops
+}
+
+func runMonitoringSubscription(sub *monitoringSubscription) {
+	ticker := time.NewTicker(sub.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sub.stop:
+			return
+		case <-ticker.C:
+			next, err := snapshotForKind(sub.kind)
+			if err != nil {
+				continue
+			}
+
+			sub.tick++
+			forceFull := sub.last == nil || sub.tick%fullSnapshotEvery == 0
+
+			var (
+				payload []byte
+				encErr  error
+			)
+			if forceFull {
+				payload, encErr = json.Marshal(next)
+			} else {
+				ops := diffSnapshots(sub.last, next)
+				if len(ops) == 0 {
+					sub.last = next
+					continue
+				}
+				payload, encErr = json.Marshal(ops)
+			}
+			sub.last = next
+			if encErr != nil {
+				continue
+			}
+
+			cPatch := C.CString(string(payload))
+			C.call_monitoring_callback(sub.callback, C.longlong(sub.id), cPatch)
+			C.free(unsafe.Pointer(cPatch))
+		}
+	}
+}
+
+//export SubscribeMonitoring
+func SubscribeMonitoring(kind *C.char, intervalMs C.int, callback C.monitoring_callback) C.longlong {
+	if kind == nil || callback == nil {
+		return -1
+	}
+
+	kindStr := C.GoString(kind)
+	switch kindStr {
+	case "varz", "connz", "subsz", "jsz", "accountz":
+	default:
+		return -1
+	}
+
+	monitoringSubsMu.Lock()
+	monitoringSubsSeq++
+	id := monitoringSubsSeq
+	sub := &monitoringSubscription{
+		id:       id,
+		kind:     kindStr,
+		interval: time.Duration(intervalMs) * time.Millisecond,
+		callback: callback,
+		stop:     make(chan struct{}),
+	}
+	monitoringSubs[id] = sub
+	monitoringSubsMu.Unlock()
+
+	go runMonitoringSubscription(sub)
+
+	return C.longlong(id)
+}
+
+//export UnsubscribeMonitoring
+func UnsubscribeMonitoring(subID C.longlong) *C.char {
+	id := int64(subID)
+
+	monitoringSubsMu.Lock()
+	sub, exists := monitoringSubs[id]
+	if exists {
+		delete(monitoringSubs, id)
+	}
+	monitoringSubsMu.Unlock()
+
+	if !exists {
+		return C.CString(fmt.Sprintf("ERROR: No subscription with id %d", id))
+	}
+	close(sub.stop)
+
+	return C.CString("OK")
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// Test SubscribeMonitoring delivers at least one snapshot then can be unsubscribed
+func TestSubscribeMonitoring_DeliversSnapshot(t *testing.T) {
+	port := 14280
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	resetTestMonitoringInvokes()
+
+	kind := cString("varz")
+	defer cFree(kind)
+
+	subID := SubscribeMonitoring(kind, 50, testMonitoringCallbackPtr())
+	if subID < 0 {
+		t.Fatal("Expected a valid subscription id")
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	response := goString(UnsubscribeMonitoring(subID))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success unsubscribing, got error: %s", response)
+	}
+
+	if testMonitoringInvokeCount() == 0 {
+		t.Fatal("Expected at least one monitoring callback invocation")
+	}
+}
+
+// Test SubscribeMonitoring rejects an unknown kind
+func TestSubscribeMonitoring_UnknownKind(t *testing.T) {
+	kind := cString("bogus")
+	defer cFree(kind)
+
+	subID := SubscribeMonitoring(kind, 50, testMonitoringCallbackPtr())
+	if subID >= 0 {
+		t.Fatal("Expected an error subscription id for unknown kind")
+	}
+}
+
+// Test UnsubscribeMonitoring on an unknown id returns an error
+func TestUnsubscribeMonitoring_UnknownID(t *testing.T) {
+	response := goString(UnsubscribeMonitoring(999999))
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error unsubscribing from unknown id")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+/*
+#include <stdlib.h>
+
+extern void testMonitoringCallback(long long subID, char* jsonPatch);
+
+static inline monitoring_callback testMonitoringCallbackPtrC(void) {
+    return (monitoring_callback)testMonitoringCallback;
+}
+*/
+import "C"
+import "sync"
+
+// testMonitoringCallback and its bookkeeping exist only to give
+// monitor_stream_test.go a monitoring_callback it can hand to
+// SubscribeMonitoring without itself importing "C" - cgo is unavailable in
+// _test.go files, so the callback and its C function-pointer cast live
+// here instead.
+var (
+	testCallbackMu      sync.Mutex
+	testCallbackInvokes []string
+)
+
+//export testMonitoringCallback
+func testMonitoringCallback(subID C.longlong, jsonPatch *C.char) {
+	testCallbackMu.Lock()
+	defer testCallbackMu.Unlock()
+	testCallbackInvokes = append(testCallbackInvokes, C.GoString(jsonPatch))
+}
+
+// testMonitoringCallbackPtr returns testMonitoringCallback as the C
+// function pointer type SubscribeMonitoring expects.
+func testMonitoringCallbackPtr() C.monitoring_callback {
+	return C.testMonitoringCallbackPtrC()
+}
+
+func resetTestMonitoringInvokes() {
+	testCallbackMu.Lock()
+	testCallbackInvokes = nil
+	testCallbackMu.Unlock()
+}
+
+func testMonitoringInvokeCount() int {
+	testCallbackMu.Lock()
+	defer testCallbackMu.Unlock()
+	return len(testCallbackInvokes)
+}
@@ -0,0 +1,442 @@
+package main
+
+/*
+#include <stdlib.h>
+
+typedef void (*monitoring_delta_callback)(long long sub_id, char* kind, char* json_deltas);
+
+static inline void call_monitoring_delta_callback(monitoring_delta_callback cb, long long subID, char* kind, char* deltas) {
+    cb(subID, kind, deltas);
+}
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// monitoringEntryKeyFields is tried, in order, against each candidate JSON
+// object when flattenMonitoringEntries needs to derive a stable key for it.
+// None of Raftz/AccountStatz/Gatewayz's concrete Go result types are part of
+// the verified public surface available in this build, so entries are keyed
+// by whichever of these fields they carry rather than by Go struct field
+// access.
+var monitoringEntryKeyFields = []string{"account", "group", "name", "gateway", "id"}
+
+// flattenMonitoringEntries reduces an arbitrary Get*z result to a flat,
+// stably-keyed map of raw JSON entries, so GetRaftzPage/GetAccountStatzPage/
+// GetGatewayzPage and SubscribeMonitoringDeltas can page and diff them the
+// same way regardless of which monitoring endpoint produced them. If the
+// marshaled payload is itself an object whose values are themselves objects
+// (Raftz's shape: group name -> group info), those keys are used directly.
+// Otherwise every top-level field is searched for the first JSON array, and
+// each element is keyed by whichever of monitoringEntryKeyFields it carries,
+// falling back to its index.
+func flattenMonitoringEntries(payload interface{}) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var asMap map[string]json.RawMessage
+	if json.Unmarshal(raw, &asMap) == nil {
+		allObjects := len(asMap) > 0
+		for _, v := range asMap {
+			var probe map[string]json.RawMessage
+			if json.Unmarshal(v, &probe) != nil {
+				allObjects = false
+				break
+			}
+		}
+		if allObjects {
+			return asMap, nil
+		}
+
+		for _, v := range asMap {
+			var arr []json.RawMessage
+			if json.Unmarshal(v, &arr) == nil && len(arr) > 0 {
+				return keyMonitoringEntries(arr), nil
+			}
+		}
+		return map[string]json.RawMessage{}, nil
+	}
+
+	var arr []json.RawMessage
+	if json.Unmarshal(raw, &arr) == nil {
+		return keyMonitoringEntries(arr), nil
+	}
+
+	return map[string]json.RawMessage{}, nil
+}
+
+func keyMonitoringEntries(arr []json.RawMessage) map[string]json.RawMessage {
+	entries := make(map[string]json.RawMessage, len(arr))
+	for i, item := range arr {
+		key := ""
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(item, &fields) == nil {
+			for _, candidate := range monitoringEntryKeyFields {
+				if raw, ok := fields[candidate]; ok {
+					var s string
+					if json.Unmarshal(raw, &s) == nil && s != "" {
+						key = s
+						break
+					}
+				}
+			}
+		}
+		if key == "" {
+			key = strconv.Itoa(i)
+		}
+		entries[key] = item
+	}
+	return entries
+}
+
+// monitoringPage is the response shape shared by GetRaftzPage,
+// GetAccountStatzPage, and GetGatewayzPage.
+type monitoringPage struct {
+	Total   int                        `json:"total"`
+	Offset  int                        `json:"offset"`
+	Limit   int                        `json:"limit"`
+	Entries map[string]json.RawMessage `json:"entries"`
+}
+
+// paginateMonitoringEntries slices entries (sorted by key for a stable
+// ordering across calls) to [offset, offset+limit). A non-positive limit
+// means "no limit" - everything from offset to the end.
+func paginateMonitoringEntries(entries map[string]json.RawMessage, offset, limit int) monitoringPage {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	page := monitoringPage{Total: len(keys), Offset: offset, Limit: limit, Entries: map[string]json.RawMessage{}}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(keys) {
+		return page
+	}
+	end := len(keys)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	for _, k := range keys[offset:end] {
+		page.Entries[k] = entries[k]
+	}
+	return page
+}
+
+func marshalMonitoringPage(page monitoringPage) *C.char {
+	jsonBytes, err := json.Marshal(page)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal monitoring page: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// GetRaftzPage returns a single page of Raft group status, avoiding the cost
+// of marshaling every group (and holding serverMu for the duration) when a
+// caller only wants to render one page of a console.
+//
+//export GetRaftzPage
+func GetRaftzPage(accountFilter *C.char, groupFilter *C.char, offset C.int, limit C.int) *C.char {
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	opts := &server.RaftzOptions{}
+	if accountFilter != nil {
+		if s := C.GoString(accountFilter); s != "" {
+			opts.AccountFilter = s
+		}
+	}
+	if groupFilter != nil {
+		if s := C.GoString(groupFilter); s != "" {
+			opts.GroupFilter = s
+		}
+	}
+
+	// Note: Raftz no longer returns an error in NATS 2.12+
+	entries, err := flattenMonitoringEntries(srv.Raftz(opts))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to get Raft status: %v", err))
+	}
+
+	return marshalMonitoringPage(paginateMonitoringEntries(entries, int(offset), int(limit)))
+}
+
+// GetAccountStatzPage returns a single page of per-account statistics.
+//
+//export GetAccountStatzPage
+func GetAccountStatzPage(accountFilter *C.char, offset C.int, limit C.int) *C.char {
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	opts := &server.AccountStatzOptions{
+		IncludeUnused: true,
+	}
+	if accountFilter != nil {
+		if s := C.GoString(accountFilter); s != "" {
+			opts.Accounts = []string{s}
+		}
+	}
+
+	statz, err := srv.AccountStatz(opts)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to get account statistics: %v", err))
+	}
+
+	entries, err := flattenMonitoringEntries(statz)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to get account statistics: %v", err))
+	}
+
+	return marshalMonitoringPage(paginateMonitoringEntries(entries, int(offset), int(limit)))
+}
+
+// GetGatewayzPage returns a single page of gateway status.
+//
+//export GetGatewayzPage
+func GetGatewayzPage(gatewayName *C.char, offset C.int, limit C.int) *C.char {
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	opts := &server.GatewayzOptions{}
+	if gatewayName != nil {
+		if s := C.GoString(gatewayName); s != "" {
+			opts.Name = s
+		}
+	}
+
+	gatewayz, err := srv.Gatewayz(opts)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to get gateway info: %v", err))
+	}
+
+	entries, err := flattenMonitoringEntries(gatewayz)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to get gateway info: %v", err))
+	}
+
+	return marshalMonitoringPage(paginateMonitoringEntries(entries, int(offset), int(limit)))
+}
+
+// snapshotMonitoringEntries fetches the current, flattened entry set for one
+// of the delta-eligible monitoring kinds.
+func snapshotMonitoringEntries(kind string) (map[string]json.RawMessage, error) {
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return nil, fmt.Errorf("server not running")
+	}
+
+	switch kind {
+	case "raftz":
+		return flattenMonitoringEntries(srv.Raftz(nil))
+	case "accountz":
+		statz, err := srv.AccountStatz(&server.AccountStatzOptions{IncludeUnused: true})
+		if err != nil {
+			return nil, err
+		}
+		return flattenMonitoringEntries(statz)
+	case "gatewayz":
+		gatewayz, err := srv.Gatewayz(nil)
+		if err != nil {
+			return nil, err
+		}
+		return flattenMonitoringEntries(gatewayz)
+	default:
+		return nil, fmt.Errorf("unknown monitoring kind %q", kind)
+	}
+}
+
+func hashMonitoringEntries(entries map[string]json.RawMessage) map[string]uint64 {
+	hashes := make(map[string]uint64, len(entries))
+	for key, raw := range entries {
+		h := fnv.New64()
+		h.Write(raw)
+		hashes[key] = h.Sum64()
+	}
+	return hashes
+}
+
+// monitoringDelta is one kind's worth of change since the previous poll:
+// entries added or updated carry their new raw JSON, removed entries carry
+// only their key.
+type monitoringDelta struct {
+	Kind    string                     `json:"kind"`
+	Added   map[string]json.RawMessage `json:"added,omitempty"`
+	Removed []string                   `json:"removed,omitempty"`
+	Updated map[string]json.RawMessage `json:"updated,omitempty"`
+}
+
+func (d monitoringDelta) empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Updated) == 0
+}
+
+type monitoringDeltaSubscription struct {
+	id       int64
+	kinds    []string
+	interval time.Duration
+	callback C.monitoring_delta_callback
+	hashes   map[string]map[string]uint64 // kind -> entry key -> fnv64 hash
+	stop     chan struct{}
+}
+
+var (
+	monitoringDeltaSubsMu  sync.Mutex
+	monitoringDeltaSubs    = make(map[int64]*monitoringDeltaSubscription)
+	monitoringDeltaSubsSeq int64
+)
+
+func runMonitoringDeltaSubscription(sub *monitoringDeltaSubscription) {
+	ticker := time.NewTicker(sub.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-sub.stop:
+			return
+		case <-ticker.C:
+			for _, kind := range sub.kinds {
+				entries, err := snapshotMonitoringEntries(kind)
+				if err != nil {
+					continue
+				}
+				nextHashes := hashMonitoringEntries(entries)
+				prevHashes := sub.hashes[kind]
+
+				delta := monitoringDelta{Kind: kind}
+				for key, h := range nextHashes {
+					prevHash, existed := prevHashes[key]
+					if !existed {
+						if delta.Added == nil {
+							delta.Added = map[string]json.RawMessage{}
+						}
+						delta.Added[key] = entries[key]
+						continue
+					}
+					if prevHash != h {
+						if delta.Updated == nil {
+							delta.Updated = map[string]json.RawMessage{}
+						}
+						delta.Updated[key] = entries[key]
+					}
+				}
+				for key := range prevHashes {
+					if _, exists := nextHashes[key]; !exists {
+						delta.Removed = append(delta.Removed, key)
+					}
+				}
+				sub.hashes[kind] = nextHashes
+
+				if delta.empty() {
+					continue
+				}
+
+				payload, err := json.Marshal(delta)
+				if err != nil {
+					continue
+				}
+				cKind := C.CString(kind)
+				cPayload := C.CString(string(payload))
+				C.call_monitoring_delta_callback(sub.callback, C.longlong(sub.id), cKind, cPayload)
+				C.free(unsafe.Pointer(cKind))
+				C.free(unsafe.Pointer(cPayload))
+			}
+		}
+	}
+}
+
+// SubscribeMonitoringDeltas starts a background goroutine that polls one or
+// more monitoring kinds (a comma-separated list drawn from "raftz",
+// "accountz", "gatewayz") every intervalMs, hashes each entry's marshaled
+// JSON with fnv64, and invokes callback once per kind with only the entries
+// that were added, removed, or changed hash since the previous poll - so an
+// embedder can maintain a live console without re-fetching (or re-diffing)
+// the full collection on every tick. The first poll after subscribing
+// reports every entry as added, since there is no prior snapshot to diff
+// against.
+//
+//export SubscribeMonitoringDeltas
+func SubscribeMonitoringDeltas(kinds *C.char, intervalMs C.int, callback C.monitoring_delta_callback) C.longlong {
+	if kinds == nil || callback == nil {
+		return -1
+	}
+
+	var kindList []string
+	for _, k := range strings.Split(C.GoString(kinds), ",") {
+		k = strings.TrimSpace(k)
+		switch k {
+		case "raftz", "accountz", "gatewayz":
+			kindList = append(kindList, k)
+		}
+	}
+	if len(kindList) == 0 {
+		return -1
+	}
+
+	monitoringDeltaSubsMu.Lock()
+	monitoringDeltaSubsSeq++
+	id := monitoringDeltaSubsSeq
+	sub := &monitoringDeltaSubscription{
+		id:       id,
+		kinds:    kindList,
+		interval: time.Duration(intervalMs) * time.Millisecond,
+		callback: callback,
+		hashes:   make(map[string]map[string]uint64),
+		stop:     make(chan struct{}),
+	}
+	monitoringDeltaSubs[id] = sub
+	monitoringDeltaSubsMu.Unlock()
+
+	go runMonitoringDeltaSubscription(sub)
+
+	return C.longlong(id)
+}
+
+// UnsubscribeMonitoringDeltas stops the background goroutine for subID and
+// frees its callback registration.
+//
+//export UnsubscribeMonitoringDeltas
+func UnsubscribeMonitoringDeltas(subID C.longlong) *C.char {
+	id := int64(subID)
+
+	monitoringDeltaSubsMu.Lock()
+	sub, exists := monitoringDeltaSubs[id]
+	if exists {
+		delete(monitoringDeltaSubs, id)
+	}
+	monitoringDeltaSubsMu.Unlock()
+
+	if !exists {
+		return C.CString(fmt.Sprintf("ERROR: No subscription with id %d", id))
+	}
+	close(sub.stop)
+
+	return C.CString("OK")
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// Test GetRaftzPage returns a well-formed, bounded page even when the
+// cluster has no Raft groups yet.
+func TestGetRaftzPage_EmptyCluster(t *testing.T) {
+	port := 14460
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	response := goStringFree(GetRaftzPage(nil, nil, 0, 10))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success getting Raft page, got: %s", response)
+	}
+}
+
+// Test GetAccountStatzPage paginates down to an empty page once offset
+// reaches past the total entry count.
+func TestGetAccountStatzPage_OffsetPastEnd(t *testing.T) {
+	port := 14461
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	response := goStringFree(GetAccountStatzPage(nil, 1_000_000, 10))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success getting account statz page, got: %s", response)
+	}
+
+	var page monitoringPage
+	if err := json.Unmarshal([]byte(response), &page); err != nil {
+		t.Fatalf("Failed to parse monitoring page: %v", err)
+	}
+	if len(page.Entries) != 0 {
+		t.Errorf("Expected no entries past the end of the collection, got %d", len(page.Entries))
+	}
+}
+
+// Test SubscribeMonitoringDeltas delivers at least one delta for the global
+// account, then can be unsubscribed cleanly.
+func TestSubscribeMonitoringDeltas_DeliversDelta(t *testing.T) {
+	port := 14462
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	resetTestDeltaCallbackInvokes()
+
+	kinds := cString("accountz")
+	defer cFree(kinds)
+
+	subID := SubscribeMonitoringDeltas(kinds, 50, testMonitoringDeltaCallbackPtr())
+	if subID < 0 {
+		t.Fatal("Expected a valid subscription id")
+	}
+
+	time.Sleep(300 * time.Millisecond)
+
+	response := goString(UnsubscribeMonitoringDeltas(subID))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success unsubscribing, got error: %s", response)
+	}
+
+	if testDeltaCallbackInvokeCount() == 0 {
+		t.Fatal("Expected at least one monitoring delta callback invocation")
+	}
+}
+
+// Test SubscribeMonitoringDeltas rejects an empty/unknown kind list.
+func TestSubscribeMonitoringDeltas_NoValidKinds(t *testing.T) {
+	kinds := cString("bogus")
+	defer cFree(kinds)
+
+	subID := SubscribeMonitoringDeltas(kinds, 50, testMonitoringDeltaCallbackPtr())
+	if subID >= 0 {
+		t.Fatal("Expected an error subscription id for an unknown kind list")
+	}
+}
+
+// Test UnsubscribeMonitoringDeltas on an unknown id returns an error.
+func TestUnsubscribeMonitoringDeltas_UnknownID(t *testing.T) {
+	response := goString(UnsubscribeMonitoringDeltas(999999))
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error unsubscribing from unknown id")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+/*
+#include <stdlib.h>
+
+extern void testMonitoringDeltaCallback(long long subID, char* kind, char* jsonDeltas);
+
+static inline monitoring_delta_callback testMonitoringDeltaCallbackPtrC(void) {
+    return (monitoring_delta_callback)testMonitoringDeltaCallback;
+}
+*/
+import "C"
+import "sync"
+
+// testMonitoringDeltaCallback and its bookkeeping exist only to give
+// monitoring_pagination_test.go a monitoring_delta_callback it can hand to
+// SubscribeMonitoringDeltas without itself importing "C" - cgo is
+// unavailable in _test.go files, so the callback and its C function-pointer
+// cast live here instead.
+var (
+	testDeltaCallbackMu      sync.Mutex
+	testDeltaCallbackInvokes []string
+)
+
+//export testMonitoringDeltaCallback
+func testMonitoringDeltaCallback(subID C.longlong, kind *C.char, jsonDeltas *C.char) {
+	testDeltaCallbackMu.Lock()
+	defer testDeltaCallbackMu.Unlock()
+	testDeltaCallbackInvokes = append(testDeltaCallbackInvokes, C.GoString(kind)+":"+C.GoString(jsonDeltas))
+}
+
+// testMonitoringDeltaCallbackPtr returns testMonitoringDeltaCallback as the
+// C function pointer type SubscribeMonitoringDeltas expects.
+func testMonitoringDeltaCallbackPtr() C.monitoring_delta_callback {
+	return C.testMonitoringDeltaCallbackPtrC()
+}
+
+func resetTestDeltaCallbackInvokes() {
+	testDeltaCallbackMu.Lock()
+	testDeltaCallbackInvokes = nil
+	testDeltaCallbackMu.Unlock()
+}
+
+func testDeltaCallbackInvokeCount() int {
+	testDeltaCallbackMu.Lock()
+	defer testDeltaCallbackMu.Unlock()
+	return len(testDeltaCallbackInvokes)
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
 	"sync"
 	"time"
 	"unsafe"
@@ -20,36 +21,43 @@ import (
 var (
 	// Map of server instances by port (supports multiple servers per process)
 	natsServers = make(map[int]*server.Server)
-	currentPort int // Most recently started server port (for GetServerInfo/GetClientURL)
-	serverMu    sync.Mutex
+	// Map of the options each server was last (re)started or reloaded with,
+	// so runtime mutations like AddLeafRemote can patch a single field
+	// without losing the rest of the configuration.
+	natsServerOpts = make(map[int]*server.Options)
+	currentPort    int // Most recently started server port (for GetServerInfo/GetClientURL)
+	serverMu       sync.Mutex
 )
 
 // ServerConfig represents the configuration for the NATS server
 type ServerConfig struct {
-	Host               string         `json:"host"`
-	Port               int            `json:"port"`
-	MaxPayload         int            `json:"max_payload"`
-	MaxControlLine     int            `json:"max_control_line"`
-	PingInterval       int            `json:"ping_interval"`
-	MaxPingsOut        int            `json:"max_pings_out"`
-	WriteDeadline      int            `json:"write_deadline"`
-	Debug              bool           `json:"debug"`
-	Trace              bool           `json:"trace"`
-	LogFile            string         `json:"log_file"`
-	LogTimeUtc         bool           `json:"log_time_utc"`
-	LogFileSize        int64          `json:"log_file_size"`
-	Jetstream          bool           `json:"jetstream"`
-	JetstreamStoreDir  string         `json:"jetstream_store_dir"`
-	JetstreamMaxMemory int64          `json:"jetstream_max_memory"`
-	JetstreamMaxStore  int64          `json:"jetstream_max_store"`
-	JetstreamDomain    string         `json:"jetstream_domain"`
-	JetstreamUniqueTag string         `json:"jetstream_unique_tag"`
-	HTTPPort           int            `json:"http_port"`
-	HTTPHost           string         `json:"http_host"`
-	HTTPSPort          int            `json:"https_port"`
-	Auth               AuthConfig     `json:"auth"`
-	LeafNode           LeafNodeConfig `json:"leaf_node"`
-	Cluster            ClusterConfig  `json:"cluster"`
+	Host               string          `json:"host"`
+	Port               int             `json:"port"`
+	MaxPayload         int             `json:"max_payload"`
+	MaxControlLine     int             `json:"max_control_line"`
+	PingInterval       int             `json:"ping_interval"`
+	MaxPingsOut        int             `json:"max_pings_out"`
+	WriteDeadline      int             `json:"write_deadline"`
+	Debug              bool            `json:"debug"`
+	Trace              bool            `json:"trace"`
+	LogFile            string          `json:"log_file"`
+	LogTimeUtc         bool            `json:"log_time_utc"`
+	LogFileSize        int64           `json:"log_file_size"`
+	Jetstream          bool            `json:"jetstream"`
+	JetstreamStoreDir  string          `json:"jetstream_store_dir"`
+	JetstreamMaxMemory int64           `json:"jetstream_max_memory"`
+	JetstreamMaxStore  int64           `json:"jetstream_max_store"`
+	JetstreamDomain    string          `json:"jetstream_domain"`
+	JetstreamUniqueTag string          `json:"jetstream_unique_tag"`
+	HTTPPort           int             `json:"http_port"`
+	HTTPHost           string          `json:"http_host"`
+	HTTPSPort          int             `json:"https_port"`
+	Auth               AuthConfig      `json:"auth"`
+	LeafNode           LeafNodeConfig  `json:"leaf_node"`
+	Cluster            ClusterConfig   `json:"cluster"`
+	MQTT               MQTTConfig      `json:"mqtt"`
+	Websocket          WebsocketConfig `json:"websocket"`
+	Resolver           ResolverConfig  `json:"resolver"`
 }
 
 type AuthConfig struct {
@@ -87,13 +95,93 @@ type ClusterConfig struct {
 	TLSVerify      bool     `json:"tls_verify"`
 }
 
+// MQTTConfig configures the server's MQTT listener, letting IoT clients
+// connect to the embedded broker without a separate bridging proxy.
+type MQTTConfig struct {
+	Host         string `json:"host"`
+	Port         int    `json:"port"`
+	NoAuthUser   string `json:"no_auth_user"`
+	AuthUsername string `json:"auth_username"`
+	AuthPassword string `json:"auth_password"`
+	AuthToken    string `json:"auth_token"`
+	TLSCert      string `json:"tls_cert"`
+	TLSKey       string `json:"tls_key"`
+	TLSCACert    string `json:"tls_ca_cert"`
+	TLSVerify    bool   `json:"tls_verify"`
+}
+
+// WebsocketConfig configures the server's WebSocket listener, letting
+// browser clients connect to the embedded broker without a separate
+// bridging proxy.
+type WebsocketConfig struct {
+	Host           string   `json:"host"`
+	Port           int      `json:"port"`
+	NoTLS          bool     `json:"no_tls"`
+	SameOrigin     bool     `json:"same_origin"`
+	NoAuthUser     string   `json:"no_auth_user"`
+	AuthUsername   string   `json:"auth_username"`
+	AuthPassword   string   `json:"auth_password"`
+	AuthToken      string   `json:"auth_token"`
+	AllowedOrigins []string `json:"allowed_origins"`
+	TLSCert        string   `json:"tls_cert"`
+	TLSKey         string   `json:"tls_key"`
+	TLSCACert      string   `json:"tls_ca_cert"`
+	TLSVerify      bool     `json:"tls_verify"`
+}
+
+// ResolverConfig configures the decentralized-auth account resolver, so a
+// server can be started already trusting an operator and able to look up
+// accounts pushed to it via PushAccountJWT instead of requiring every
+// account to be registered by hand through RegisterAccountJWT.
+type ResolverConfig struct {
+	Type          string   `json:"type"` // "mem", "full" (directory-backed), or "cache"
+	Dir           string   `json:"dir"`
+	AllowDelete   bool     `json:"allow_delete"`
+	Timeout       int      `json:"timeout"`        // seconds
+	Preload       []string `json:"preload"`        // account JWTs to seed a mem resolver with
+	Operators     []string `json:"operators"`      // trusted operator JWTs
+	SystemAccount string   `json:"system_account"` // system account public key
+}
+
 type AccountConfig struct {
-	Name             string `json:"name"`
-	Description      string `json:"description"`
-	MaxConnections   int    `json:"max_connections"`
-	MaxSubscriptions int    `json:"max_subscriptions"`
-	MaxData          int64  `json:"max_data"`
-	MaxPayload       int64  `json:"max_payload"`
+	Name             string         `json:"name"`
+	Description      string         `json:"description"`
+	MaxConnections   int            `json:"max_connections"`
+	MaxSubscriptions int            `json:"max_subscriptions"`
+	MaxData          int64          `json:"max_data"`
+	MaxPayload       int64          `json:"max_payload"`
+	Exports          []ExportConfig `json:"exports"`
+	Imports          []ImportConfig `json:"imports"`
+}
+
+// LatencyConfig requests latency sampling on a service export, mirroring
+// nats-server's service latency tracking.
+type LatencyConfig struct {
+	Sampling int    `json:"sampling"` // percentage, 1-100
+	Subject  string `json:"subject"`  // subject latency results are published to
+}
+
+// ExportConfig describes one stream or service export an account offers to
+// others, applied by ApplyAccountConfig in account_imports_exports.go.
+type ExportConfig struct {
+	Subject       string        `json:"subject"`
+	Type          string        `json:"type"` // "stream" or "service"
+	TokenRequired bool          `json:"token_required"`
+	Accounts      []string      `json:"accounts"`      // account public keys allowed to import, when TokenRequired
+	ResponseType  string        `json:"response_type"` // "singleton", "stream", or "chunked" (service exports only)
+	Latency       LatencyConfig `json:"latency"`
+	AllowTrace    bool          `json:"allow_trace"`
+}
+
+// ImportConfig describes one stream or service import an account consumes
+// from another, applied by ApplyAccountConfig in account_imports_exports.go.
+type ImportConfig struct {
+	Account      string `json:"account"`
+	Subject      string `json:"subject"`
+	LocalSubject string `json:"local_subject"`
+	Type         string `json:"type"` // "stream" or "service"
+	Token        string `json:"token"`
+	Share        bool   `json:"share"`
 }
 
 // convertToNatsOptions converts our config to NATS server options
@@ -213,6 +301,105 @@ func convertToNatsOptions(config *ServerConfig) *server.Options {
 		}
 	}
 
+	// Configure MQTT if port is set
+	if config.MQTT.Port > 0 {
+		opts.MQTT.Host = config.MQTT.Host
+		opts.MQTT.Port = config.MQTT.Port
+		opts.MQTT.NoAuthUser = config.MQTT.NoAuthUser
+
+		if config.MQTT.AuthUsername != "" && config.MQTT.AuthPassword != "" {
+			opts.MQTT.Username = config.MQTT.AuthUsername
+			opts.MQTT.Password = config.MQTT.AuthPassword
+		} else if config.MQTT.AuthToken != "" {
+			opts.MQTT.Token = config.MQTT.AuthToken
+		}
+
+		if config.MQTT.TLSCert != "" && config.MQTT.TLSKey != "" {
+			tlsConfig, err := server.GenTLSConfig(&server.TLSConfigOpts{
+				CertFile: config.MQTT.TLSCert,
+				KeyFile:  config.MQTT.TLSKey,
+				CaFile:   config.MQTT.TLSCACert,
+				Verify:   config.MQTT.TLSVerify,
+			})
+			if err == nil {
+				opts.MQTT.TLSConfig = tlsConfig
+			}
+			// If TLS config generation fails, skip TLS (logged elsewhere if needed)
+		}
+	}
+
+	// Configure WebSocket if port is set
+	if config.Websocket.Port > 0 {
+		opts.Websocket.Host = config.Websocket.Host
+		opts.Websocket.Port = config.Websocket.Port
+		opts.Websocket.NoTLS = config.Websocket.NoTLS
+		opts.Websocket.SameOrigin = config.Websocket.SameOrigin
+		opts.Websocket.NoAuthUser = config.Websocket.NoAuthUser
+
+		if len(config.Websocket.AllowedOrigins) > 0 {
+			opts.Websocket.AllowedOrigins = config.Websocket.AllowedOrigins
+		}
+
+		if config.Websocket.AuthUsername != "" && config.Websocket.AuthPassword != "" {
+			opts.Websocket.Username = config.Websocket.AuthUsername
+			opts.Websocket.Password = config.Websocket.AuthPassword
+		} else if config.Websocket.AuthToken != "" {
+			opts.Websocket.Token = config.Websocket.AuthToken
+		}
+
+		if config.Websocket.TLSCert != "" && config.Websocket.TLSKey != "" {
+			tlsConfig, err := server.GenTLSConfig(&server.TLSConfigOpts{
+				CertFile: config.Websocket.TLSCert,
+				KeyFile:  config.Websocket.TLSKey,
+				CaFile:   config.Websocket.TLSCACert,
+				Verify:   config.Websocket.TLSVerify,
+			})
+			if err == nil {
+				opts.Websocket.TLSConfig = tlsConfig
+			}
+			// If TLS config generation fails, skip TLS (logged elsewhere if needed)
+		}
+	}
+
+	// Configure the trusted-operator / account-resolver trust chain if
+	// provided, so the server comes up already able to verify and look up
+	// accounts instead of relying solely on RegisterAccountJWT calls made
+	// after startup.
+	if config.Resolver.Type != "" {
+		for _, operatorJWT := range config.Resolver.Operators {
+			if claims, err := jwt.DecodeOperatorClaims(operatorJWT); err == nil {
+				opts.TrustedOperators = append(opts.TrustedOperators, claims)
+			}
+			// If decoding fails, skip it (logged elsewhere if needed)
+		}
+
+		switch config.Resolver.Type {
+		case "mem":
+			resolver := &server.MemAccResolver{}
+			for _, accountJWT := range config.Resolver.Preload {
+				if claims, err := jwt.DecodeAccountClaims(accountJWT); err == nil {
+					resolver.Store(claims.Subject, accountJWT)
+				}
+			}
+			opts.AccountResolver = resolver
+		case "full", "cache":
+			ttl := time.Duration(config.Resolver.Timeout) * time.Second
+			deleteType := server.NoDelete
+			if config.Resolver.AllowDelete {
+				deleteType = server.RenameDeleted
+			}
+			resolver, err := server.NewDirAccResolver(config.Resolver.Dir, 0, ttl, deleteType)
+			if err == nil {
+				opts.AccountResolver = resolver
+			}
+			// If the resolver can't be created, skip it (logged elsewhere if needed)
+		}
+
+		if config.Resolver.SystemAccount != "" {
+			opts.SystemAccount = config.Resolver.SystemAccount
+		}
+	}
+
 	return opts
 }
 
@@ -243,6 +430,7 @@ func createAndStartServer(opts *server.Options) error {
 		}
 
 		delete(natsServers, port)
+		clearFastState(port)
 	}
 
 	// Create new server
@@ -264,7 +452,9 @@ func createAndStartServer(opts *server.Options) error {
 
 	// Store server by port and mark as current
 	natsServers[port] = newServer
-	currentPort = port
+	natsServerOpts[port] = opts
+	setCurrentPortLocked(port)
+	publishFastState(port, newServer, opts.JetStream)
 
 	return nil
 }
@@ -326,25 +516,8 @@ func ShutdownServer() {
 	// Shutdown the current server (most recently started)
 	if currentPort > 0 {
 		if srv, exists := natsServers[currentPort]; exists {
-			srv.Shutdown()
-
-			// Wait for shutdown with timeout to prevent hanging
-			shutdownComplete := make(chan struct{})
-			go func() {
-				srv.WaitForShutdown()
-				close(shutdownComplete)
-			}()
-
-			// Wait max 10 seconds for graceful shutdown
-			select {
-			case <-shutdownComplete:
-				// Shutdown completed gracefully
-			case <-time.After(10 * time.Second):
-				// Timeout - force cleanup anyway
-			}
-
-			delete(natsServers, currentPort)
-			currentPort = 0
+			shutdownAndForgetLocked(currentPort, srv)
+			setCurrentPortLocked(0)
 		}
 	}
 }
@@ -371,7 +544,7 @@ func EnterLameDuckMode() *C.char {
 func SetCurrentPort(port C.int) {
 	serverMu.Lock()
 	defer serverMu.Unlock()
-	currentPort = int(port)
+	setCurrentPortLocked(int(port))
 }
 
 //export GetClientURL
@@ -485,6 +658,7 @@ func ReloadConfigFromFile(configFilePath *C.char) *C.char {
 	if err := srv.ReloadOptions(opts); err != nil {
 		return C.CString(fmt.Sprintf("ERROR: Failed to reload options: %v", err))
 	}
+	natsServerOpts[currentPort] = opts
 
 	return C.CString("OK")
 }
@@ -505,7 +679,7 @@ func UpdateAndReloadConfig(configJson *C.char) *C.char {
 
 	// Update currentPort to match the config being reloaded
 	// This allows switching between servers
-	currentPort = config.Port
+	setCurrentPortLocked(config.Port)
 
 	srv, exists := natsServers[currentPort]
 	if !exists || srv == nil {
@@ -515,6 +689,7 @@ func UpdateAndReloadConfig(configJson *C.char) *C.char {
 	if err := srv.ReloadOptions(opts); err != nil {
 		return C.CString(fmt.Sprintf("ERROR: Failed to reload options: %v", err))
 	}
+	natsServerOpts[currentPort] = opts
 
 	return C.CString("OK")
 }
@@ -808,9 +983,9 @@ func GetClientInfo(clientID C.ulonglong) *C.char {
 
 	// Get detailed client information using Connz with specific CID
 	opts := &server.ConnzOptions{
-		CID:                   cid,
-		Subscriptions:         true,
-		SubscriptionsDetail:   true,
+		CID:                 cid,
+		Subscriptions:       true,
+		SubscriptionsDetail: true,
 	}
 
 	connz, err := srv.Connz(opts)
@@ -1047,66 +1222,153 @@ func GetAccountStatz(accountFilter *C.char) *C.char {
 //
 //export GetServerID
 func GetServerID() *C.char {
-	serverMu.Lock()
-	defer serverMu.Unlock()
+	port := int(currentPortAtomic.Load())
+	if port == 0 {
+		return C.CString("ERROR: Server not running")
+	}
 
-	srv, exists := natsServers[currentPort]
-	if !exists || srv == nil {
+	state, exists := loadFastState(port)
+	if !exists || state.srv.Load() == nil {
 		return C.CString("ERROR: Server not running")
 	}
 
-	serverID := srv.ID()
+	serverID, _ := state.serverID.Load().(string)
 	return C.CString(serverID)
 }
 
-// GetServerName returns the server name from configuration.
+// GetServerName is a thin wrapper over GetServerNameFor for the implicit
+// "current" server, kept for callers that haven't migrated to explicit
+// handles yet.
 //
 //export GetServerName
 func GetServerName() *C.char {
-	serverMu.Lock()
-	defer serverMu.Unlock()
+	return callWithCurrentHandle(GetServerNameFor, "ERROR: Server not running")
+}
+
+// IsServerRunning is a thin wrapper over IsServerRunningFor for the
+// implicit "current" server, kept for callers that haven't migrated to
+// explicit handles yet. Returns "true" or "false" as a string.
+//
+//export IsServerRunning
+func IsServerRunning() *C.char {
+	return callWithCurrentHandle(IsServerRunningFor, "false")
+}
 
+// WaitForReadyState is a thin wrapper over WaitForReadyStateFor for the
+// implicit "current" server, kept for callers that haven't migrated to
+// explicit handles yet. Returns "true" if ready, "false" if the timeout
+// expires.
+//
+//export WaitForReadyState
+func WaitForReadyState(timeoutSeconds C.int) *C.char {
+	return callWithCurrentHandle(func(handle *C.char) *C.char {
+		return WaitForReadyStateFor(handle, timeoutSeconds)
+	}, "ERROR: Server not running")
+}
+
+// GracefulShutdownResult reports whether a GracefulShutdown call finished
+// draining in-flight work before its deadline, and how many clients (if
+// any) were still connected when the timeout forced the shutdown through.
+type GracefulShutdownResult struct {
+	Drained          bool `json:"drained"`
+	TimedOut         bool `json:"timed_out"`
+	RemainingClients int  `json:"remaining_clients"`
+}
+
+// GracefulShutdown stops the current server from accepting new client
+// connections immediately, then polls NumClients (and, when JetStream is
+// enabled, JetStreamEnabled) in a short sleep loop - mirroring how the
+// manners-style wrappers drain a net/http.Server with a wait group - until
+// either every client has disconnected or timeoutSeconds elapses. It then
+// calls srv.Shutdown() unconditionally, so a follow-up IsServerRunning
+// always reports false. Calling it again once already shut down is a
+// no-op that reports an already-drained result.
+//
+//export GracefulShutdown
+func GracefulShutdown(timeoutSeconds C.int) *C.char {
+	serverMu.Lock()
 	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+
 	if !exists || srv == nil {
-		return C.CString("ERROR: Server not running")
+		return marshalGracefulShutdownResult(GracefulShutdownResult{Drained: true})
 	}
 
-	serverName := srv.Name()
-	if serverName == "" {
-		// If no name is configured, return a default
-		return C.CString("")
+	// Stop accepting new connections right away; existing clients get the
+	// rest of the timeout to finish in-flight requests and JetStream acks.
+	srv.LameDuckShutdown()
+
+	deadline := time.Now().Add(time.Duration(timeoutSeconds) * time.Second)
+	remaining := srv.NumClients()
+	for time.Now().Before(deadline) {
+		remaining = srv.NumClients()
+		if remaining == 0 {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
 	}
+	drained := remaining == 0
+
+	srv.Shutdown()
+	srv.WaitForShutdown()
 
-	return C.CString(serverName)
+	serverMu.Lock()
+	if natsServers[currentPort] == srv {
+		clearFastState(currentPort)
+		delete(natsServers, currentPort)
+		delete(natsServerOpts, currentPort)
+		setCurrentPortLocked(0)
+	}
+	serverMu.Unlock()
+
+	return marshalGracefulShutdownResult(GracefulShutdownResult{
+		Drained:          drained,
+		TimedOut:         !drained,
+		RemainingClients: remaining,
+	})
 }
 
-// IsServerRunning checks if the server is currently running.
-// Returns "true" or "false" as a string.
+func marshalGracefulShutdownResult(result GracefulShutdownResult) *C.char {
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal shutdown result: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// IsJetStreamEnabled is a thin wrapper over IsJetStreamEnabledFor for the
+// implicit "current" server, kept for callers that haven't migrated to
+// explicit handles yet. Returns "true", "false", or an error message.
 //
-//export IsServerRunning
-func IsServerRunning() *C.char {
+//export IsJetStreamEnabled
+func IsJetStreamEnabled() *C.char {
+	return callWithCurrentHandle(IsJetStreamEnabledFor, "ERROR: Server not running")
+}
+
+// IsMQTTEnabled checks if the MQTT listener is configured and accepting
+// connections. Returns "true", "false", or an error message.
+//
+//export IsMQTTEnabled
+func IsMQTTEnabled() *C.char {
 	serverMu.Lock()
 	defer serverMu.Unlock()
 
 	srv, exists := natsServers[currentPort]
 	if !exists || srv == nil {
-		return C.CString("false")
+		return C.CString("ERROR: Server not running")
 	}
 
-	// Check if server is actually running
-	running := srv.Running()
-	if running {
+	if srv.MQTTAddr() != nil {
 		return C.CString("true")
 	}
 	return C.CString("false")
 }
 
-// WaitForReadyState blocks until the server is ready to accept connections,
-// with a timeout specified in seconds.
-// Returns "true" if ready, "false" if timeout expires.
+// GetMQTTPort returns the port the MQTT listener is bound to, or an error
+// if MQTT is not enabled.
 //
-//export WaitForReadyState
-func WaitForReadyState(timeoutSeconds C.int) *C.char {
+//export GetMQTTPort
+func GetMQTTPort() *C.char {
 	serverMu.Lock()
 	defer serverMu.Unlock()
 
@@ -1115,20 +1377,18 @@ func WaitForReadyState(timeoutSeconds C.int) *C.char {
 		return C.CString("ERROR: Server not running")
 	}
 
-	timeout := time.Duration(timeoutSeconds) * time.Second
-	ready := srv.ReadyForConnections(timeout)
-
-	if ready {
-		return C.CString("true")
+	addr := srv.MQTTAddr()
+	if addr == nil {
+		return C.CString("ERROR: MQTT is not enabled")
 	}
-	return C.CString("false")
+	return C.CString(strconv.Itoa(addr.Port))
 }
 
-// IsJetStreamEnabled checks if JetStream is enabled at the server level.
-// Returns "true", "false", or an error message.
+// IsWebsocketEnabled checks if the WebSocket listener is configured and
+// accepting connections. Returns "true", "false", or an error message.
 //
-//export IsJetStreamEnabled
-func IsJetStreamEnabled() *C.char {
+//export IsWebsocketEnabled
+func IsWebsocketEnabled() *C.char {
 	serverMu.Lock()
 	defer serverMu.Unlock()
 
@@ -1137,19 +1397,30 @@ func IsJetStreamEnabled() *C.char {
 		return C.CString("ERROR: Server not running")
 	}
 
-	// Get server variables to check JetStream configuration
-	varz, err := srv.Varz(nil)
-	if err != nil {
-		return C.CString(fmt.Sprintf("ERROR: Failed to get server info: %v", err))
+	if srv.WebsocketAddr() != nil {
+		return C.CString("true")
 	}
+	return C.CString("false")
+}
 
-	// Check if JetStream is configured
-	// Note: JetStream is now a struct, not a pointer in NATS 2.12+
-	if varz.JetStream.Config != nil && (varz.JetStream.Config.MaxMemory > 0 || varz.JetStream.Config.MaxStore > 0) {
-		return C.CString("true")
+// GetWebsocketPort returns the port the WebSocket listener is bound to, or
+// an error if WebSocket is not enabled.
+//
+//export GetWebsocketPort
+func GetWebsocketPort() *C.char {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, exists := natsServers[currentPort]
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
 	}
 
-	return C.CString("false")
+	addr := srv.WebsocketAddr()
+	if addr == nil {
+		return C.CString("ERROR: WebSocket is not enabled")
+	}
+	return C.CString(strconv.Itoa(addr.Port))
 }
 
 // GetRaftz returns Raft consensus state information.
@@ -1256,39 +1527,14 @@ func GetOpts() *C.char {
 		return C.CString("ERROR: Server not running")
 	}
 
-	// Get current server options
-	opts := srv.GetOpts()
-	if opts == nil {
+	// Get current server options as the same simplified representation
+	// config_reload_diff.go diffs across a reload - we can't serialize
+	// server.Options directly due to unexported fields
+	optsInfo := optsSnapshotLocked(srv)
+	if optsInfo == nil {
 		return C.CString("ERROR: Failed to get server options")
 	}
 
-	// Create a simplified representation of the options
-	// We can't serialize server.Options directly due to unexported fields
-	optsInfo := map[string]interface{}{
-		"host":                  opts.Host,
-		"port":                  opts.Port,
-		"max_payload":           opts.MaxPayload,
-		"max_control_line":      opts.MaxControlLine,
-		"max_pings_out":         opts.MaxPingsOut,
-		"debug":                 opts.Debug,
-		"trace":                 opts.Trace,
-		"logtime":               opts.Logtime,
-		"log_file":              opts.LogFile,
-		"log_size_limit":        opts.LogSizeLimit,
-		"jetstream":             opts.JetStream,
-		"jetstream_max_memory":  opts.JetStreamMaxMemory,
-		"jetstream_max_store":   opts.JetStreamMaxStore,
-		"jetstream_domain":      opts.JetStreamDomain,
-		"jetstream_unique_tag":  opts.JetStreamUniqueTag,
-		"store_dir":             opts.StoreDir,
-		"http_host":             opts.HTTPHost,
-		"http_port":             opts.HTTPPort,
-		"https_port":            opts.HTTPSPort,
-		"cluster_name":          opts.Cluster.Name,
-		"cluster_port":          opts.Cluster.Port,
-		"leaf_node_port":        opts.LeafNode.Port,
-	}
-
 	jsonBytes, err := json.Marshal(optsInfo)
 	if err != nil {
 		return C.CString(fmt.Sprintf("ERROR: Failed to marshal options: %v", err))
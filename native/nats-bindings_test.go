@@ -1,16 +1,12 @@
 package main
 
-/*
-#include <stdlib.h>
-*/
-import "C"
-
 import (
+	"bufio"
 	"encoding/json"
+	"net"
 	"strings"
 	"testing"
 	"time"
-	"unsafe"
 
 	"github.com/nats-io/nats-server/v2/server"
 )
@@ -37,7 +33,9 @@ func startTestServer(t *testing.T, port int) *server.Server {
 	// Store in global map
 	serverMu.Lock()
 	natsServers[port] = srv
-	currentPort = port
+	natsServerOpts[port] = opts
+	setCurrentPortLocked(port)
+	publishFastState(port, srv, opts.JetStream)
 	serverMu.Unlock()
 
 	return srv
@@ -47,6 +45,8 @@ func startTestServer(t *testing.T, port int) *server.Server {
 func stopTestServer(t *testing.T, srv *server.Server, port int) {
 	serverMu.Lock()
 	delete(natsServers, port)
+	delete(natsServerOpts, port)
+	clearFastState(port)
 	serverMu.Unlock()
 
 	srv.Shutdown()
@@ -58,15 +58,36 @@ func isErrorResponse(response string) bool {
 	return strings.HasPrefix(response, "ERROR:")
 }
 
+// dialRawClient completes the minimal NATS handshake (read INFO, send
+// CONNECT/PING, read PONG) over a raw TCP socket so tests can hold a client
+// connection open without pulling in a full client dependency.
+func dialRawClient(t *testing.T, addr string) net.Conn {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Failed to dial server: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	if _, err := reader.ReadString('\n'); err != nil { // INFO
+		t.Fatalf("Failed to read INFO: %v", err)
+	}
+	if _, err := conn.Write([]byte("CONNECT {}\r\nPING\r\n")); err != nil {
+		t.Fatalf("Failed to send CONNECT/PING: %v", err)
+	}
+	if _, err := reader.ReadString('\n'); err != nil { // PONG
+		t.Fatalf("Failed to read PONG: %v", err)
+	}
+
+	return conn
+}
+
 // Test GetConnz with server running
 func TestGetConnz_ServerRunning(t *testing.T) {
 	port := 14222
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := GetConnz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetConnz(nil))
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -88,12 +109,10 @@ func TestGetConnz_ServerRunning(t *testing.T) {
 func TestGetConnz_ServerNotRunning(t *testing.T) {
 	// Clear server state
 	serverMu.Lock()
-	currentPort = 99999 // Non-existent port
+	setCurrentPortLocked(99999) // Non-existent port
 	serverMu.Unlock()
 
-	result := GetConnz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetConnz(nil))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -110,12 +129,10 @@ func TestGetConnz_WithSubscriptionFilter(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	filter := C.CString("test.*")
-	defer C.free(unsafe.Pointer(filter))
+	filter := cString("test.*")
+	defer cFree(filter)
 
-	result := GetConnz(filter)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetConnz(filter))
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -134,9 +151,7 @@ func TestGetSubsz_ServerRunning(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := GetSubsz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetSubsz(nil))
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -155,12 +170,10 @@ func TestGetSubsz_ServerRunning(t *testing.T) {
 // Test GetSubsz without server
 func TestGetSubsz_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	result := GetSubsz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetSubsz(nil))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -191,14 +204,12 @@ func TestGetJsz_WithJetStream(t *testing.T) {
 
 	serverMu.Lock()
 	natsServers[port] = srv
-	currentPort = port
+	setCurrentPortLocked(port)
 	serverMu.Unlock()
 
 	defer stopTestServer(t, srv, port)
 
-	result := GetJsz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetJsz(nil))
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -218,12 +229,10 @@ func TestGetJsz_WithJetStream(t *testing.T) {
 // Test GetJsz without server
 func TestGetJsz_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	result := GetJsz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetJsz(nil))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -236,9 +245,7 @@ func TestGetRoutez_ServerRunning(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := GetRoutez()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetRoutez())
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -257,12 +264,10 @@ func TestGetRoutez_ServerRunning(t *testing.T) {
 // Test GetRoutez without server
 func TestGetRoutez_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	result := GetRoutez()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetRoutez())
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -275,9 +280,7 @@ func TestGetLeafz_ServerRunning(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := GetLeafz()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetLeafz())
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -292,12 +295,10 @@ func TestGetLeafz_ServerRunning(t *testing.T) {
 // Test GetLeafz without server
 func TestGetLeafz_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	result := GetLeafz()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetLeafz())
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -310,9 +311,7 @@ func TestDisconnectClientByID_ClientNotFound(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := DisconnectClientByID(C.ulonglong(99999))
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(DisconnectClientByID(cULonglong(99999)))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error for non-existent client")
@@ -326,12 +325,10 @@ func TestDisconnectClientByID_ClientNotFound(t *testing.T) {
 // Test DisconnectClientByID without server
 func TestDisconnectClientByID_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	result := DisconnectClientByID(C.ulonglong(1))
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(DisconnectClientByID(cULonglong(1)))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -344,9 +341,7 @@ func TestGetClientInfo_ClientNotFound(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := GetClientInfo(C.ulonglong(99999))
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetClientInfo(cULonglong(99999)))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error for non-existent client")
@@ -360,12 +355,10 @@ func TestGetClientInfo_ClientNotFound(t *testing.T) {
 // Test GetClientInfo without server
 func TestGetClientInfo_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	result := GetClientInfo(C.ulonglong(1))
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetClientInfo(cULonglong(1)))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -378,9 +371,7 @@ func TestGetAccountz_ServerRunning(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := GetAccountz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetAccountz(nil))
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -399,12 +390,10 @@ func TestGetAccountz_ServerRunning(t *testing.T) {
 // Test GetAccountz without server
 func TestGetAccountz_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	result := GetAccountz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetAccountz(nil))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -417,9 +406,7 @@ func TestGetVarz_ServerRunning(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := GetVarz()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetVarz())
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -442,12 +429,10 @@ func TestGetVarz_ServerRunning(t *testing.T) {
 // Test GetVarz without server
 func TestGetVarz_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	result := GetVarz()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetVarz())
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -460,9 +445,7 @@ func TestGetGatewayz_ServerRunning(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := GetGatewayz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetGatewayz(nil))
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -477,12 +460,10 @@ func TestGetGatewayz_ServerRunning(t *testing.T) {
 // Test GetGatewayz without server
 func TestGetGatewayz_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	result := GetGatewayz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetGatewayz(nil))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -500,31 +481,31 @@ func TestConcurrentMonitoringCalls(t *testing.T) {
 
 	go func() {
 		result := GetConnz(nil)
-		C.free(unsafe.Pointer(result))
+		cFree(result)
 		done <- true
 	}()
 
 	go func() {
 		result := GetSubsz(nil)
-		C.free(unsafe.Pointer(result))
+		cFree(result)
 		done <- true
 	}()
 
 	go func() {
 		result := GetRoutez()
-		C.free(unsafe.Pointer(result))
+		cFree(result)
 		done <- true
 	}()
 
 	go func() {
 		result := GetVarz()
-		C.free(unsafe.Pointer(result))
+		cFree(result)
 		done <- true
 	}()
 
 	go func() {
 		result := GetAccountz(nil)
-		C.free(unsafe.Pointer(result))
+		cFree(result)
 		done <- true
 	}()
 
@@ -541,20 +522,18 @@ func TestJSONMarshaling_EmptyResults(t *testing.T) {
 	defer stopTestServer(t, srv, port)
 
 	// All endpoints should return valid JSON even with no data
-	endpoints := []func() *C.char{
-		func() *C.char { return GetConnz(nil) },
-		func() *C.char { return GetSubsz(nil) },
-		func() *C.char { return GetRoutez() },
-		func() *C.char { return GetLeafz() },
-		func() *C.char { return GetVarz() },
-		func() *C.char { return GetAccountz(nil) },
-		func() *C.char { return GetGatewayz(nil) },
+	endpoints := []func() string{
+		func() string { return goStringFree(GetConnz(nil)) },
+		func() string { return goStringFree(GetSubsz(nil)) },
+		func() string { return goStringFree(GetRoutez()) },
+		func() string { return goStringFree(GetLeafz()) },
+		func() string { return goStringFree(GetVarz()) },
+		func() string { return goStringFree(GetAccountz(nil)) },
+		func() string { return goStringFree(GetGatewayz(nil)) },
 	}
 
 	for i, endpoint := range endpoints {
-		result := endpoint()
-		response := C.GoString(result)
-		C.free(unsafe.Pointer(result))
+		response := endpoint()
 
 		if isErrorResponse(response) {
 			t.Errorf("Endpoint %d returned error: %s", i, response)
@@ -592,13 +571,11 @@ func TestServerStateConsistency(t *testing.T) {
 		t.Fatal("Server 2 not ready")
 	}
 	natsServers[port2] = srv2
-	currentPort = port2
+	setCurrentPortLocked(port2)
 	serverMu.Unlock()
 
 	// Test that we're getting data from port2
-	result := GetVarz()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetVarz())
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success from server 2, got error: %s", response)
@@ -624,12 +601,10 @@ func TestRegisterAccount_Success(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	accountName := C.CString("TEST_ACCOUNT_001")
-	defer C.free(unsafe.Pointer(accountName))
+	accountName := cString("TEST_ACCOUNT_001")
+	defer cFree(accountName)
 
-	result := RegisterAccount(accountName)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(RegisterAccount(accountName))
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -660,13 +635,13 @@ func TestRegisterAccount_Duplicate(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	accountName := C.CString("DUPLICATE_ACCOUNT")
-	defer C.free(unsafe.Pointer(accountName))
+	accountName := cString("DUPLICATE_ACCOUNT")
+	defer cFree(accountName)
 
 	// Register account first time
 	result1 := RegisterAccount(accountName)
-	response1 := C.GoString(result1)
-	C.free(unsafe.Pointer(result1))
+	response1 := goString(result1)
+	cFree(result1)
 
 	if isErrorResponse(response1) {
 		t.Fatalf("First registration should succeed, got error: %s", response1)
@@ -674,8 +649,8 @@ func TestRegisterAccount_Duplicate(t *testing.T) {
 
 	// Try to register same account again (should fail)
 	result2 := RegisterAccount(accountName)
-	response2 := C.GoString(result2)
-	C.free(unsafe.Pointer(result2))
+	response2 := goString(result2)
+	cFree(result2)
 
 	if !isErrorResponse(response2) {
 		t.Fatal("Expected error for duplicate account registration")
@@ -689,15 +664,13 @@ func TestRegisterAccount_Duplicate(t *testing.T) {
 // Test RegisterAccount without server
 func TestRegisterAccount_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	accountName := C.CString("TEST_ACCOUNT")
-	defer C.free(unsafe.Pointer(accountName))
+	accountName := cString("TEST_ACCOUNT")
+	defer cFree(accountName)
 
-	result := RegisterAccount(accountName)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(RegisterAccount(accountName))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -714,9 +687,7 @@ func TestRegisterAccount_NullName(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := RegisterAccount(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(RegisterAccount(nil))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error for null account name")
@@ -734,16 +705,14 @@ func TestLookupAccount_Success(t *testing.T) {
 	defer stopTestServer(t, srv, port)
 
 	// Register an account first
-	accountName := C.CString("LOOKUP_TEST_ACCOUNT")
-	defer C.free(unsafe.Pointer(accountName))
+	accountName := cString("LOOKUP_TEST_ACCOUNT")
+	defer cFree(accountName)
 
 	registerResult := RegisterAccount(accountName)
-	C.free(unsafe.Pointer(registerResult))
+	cFree(registerResult)
 
 	// Now lookup the account
-	result := LookupAccount(accountName)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(LookupAccount(accountName))
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -770,12 +739,10 @@ func TestLookupAccount_NotFound(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	accountName := C.CString("NONEXISTENT_ACCOUNT")
-	defer C.free(unsafe.Pointer(accountName))
+	accountName := cString("NONEXISTENT_ACCOUNT")
+	defer cFree(accountName)
 
-	result := LookupAccount(accountName)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(LookupAccount(accountName))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error for non-existent account")
@@ -789,15 +756,13 @@ func TestLookupAccount_NotFound(t *testing.T) {
 // Test LookupAccount without server
 func TestLookupAccount_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	accountName := C.CString("TEST_ACCOUNT")
-	defer C.free(unsafe.Pointer(accountName))
+	accountName := cString("TEST_ACCOUNT")
+	defer cFree(accountName)
 
-	result := LookupAccount(accountName)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(LookupAccount(accountName))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -811,20 +776,18 @@ func TestGetAccountStatz_Success(t *testing.T) {
 	defer stopTestServer(t, srv, port)
 
 	// Register some test accounts
-	account1 := C.CString("STATS_ACCOUNT_001")
-	account2 := C.CString("STATS_ACCOUNT_002")
-	defer C.free(unsafe.Pointer(account1))
-	defer C.free(unsafe.Pointer(account2))
+	account1 := cString("STATS_ACCOUNT_001")
+	account2 := cString("STATS_ACCOUNT_002")
+	defer cFree(account1)
+	defer cFree(account2)
 
 	reg1 := RegisterAccount(account1)
 	reg2 := RegisterAccount(account2)
-	C.free(unsafe.Pointer(reg1))
-	C.free(unsafe.Pointer(reg2))
+	cFree(reg1)
+	cFree(reg2)
 
 	// Get statistics for all accounts
-	result := GetAccountStatz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetAccountStatz(nil))
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -858,16 +821,14 @@ func TestGetAccountStatz_WithFilter(t *testing.T) {
 	defer stopTestServer(t, srv, port)
 
 	// Register a test account
-	accountName := C.CString("FILTERED_ACCOUNT")
-	defer C.free(unsafe.Pointer(accountName))
+	accountName := cString("FILTERED_ACCOUNT")
+	defer cFree(accountName)
 
 	reg := RegisterAccount(accountName)
-	C.free(unsafe.Pointer(reg))
+	cFree(reg)
 
 	// Get statistics for specific account
-	result := GetAccountStatz(accountName)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetAccountStatz(accountName))
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -887,12 +848,10 @@ func TestGetAccountStatz_WithFilter(t *testing.T) {
 // Test GetAccountStatz without server
 func TestGetAccountStatz_ServerNotRunning(t *testing.T) {
 	serverMu.Lock()
-	currentPort = 99999
+	setCurrentPortLocked(99999)
 	serverMu.Unlock()
 
-	result := GetAccountStatz(nil)
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetAccountStatz(nil))
 
 	if !isErrorResponse(response) {
 		t.Fatal("Expected error when server not running")
@@ -909,9 +868,7 @@ func TestGetServerID_Success(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := GetServerID()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetServerID())
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -928,11 +885,9 @@ func TestGetServerID_Success(t *testing.T) {
 // TestGetServerID_ServerNotRunning tests getting server ID when server is not running.
 func TestGetServerID_ServerNotRunning(t *testing.T) {
 	// Don't start a server
-	currentPort = 14242
+	setCurrentPortLocked(14242)
 
-	result := GetServerID()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetServerID())
 
 	if !isErrorResponse(response) {
 		t.Errorf("Expected error response, got: %s", response)
@@ -949,9 +904,7 @@ func TestGetServerName_Success(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := GetServerName()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetServerName())
 
 	if isErrorResponse(response) {
 		t.Fatalf("Expected success, got error: %s", response)
@@ -964,11 +917,9 @@ func TestGetServerName_Success(t *testing.T) {
 // TestGetServerName_ServerNotRunning tests getting server name when server is not running.
 func TestGetServerName_ServerNotRunning(t *testing.T) {
 	// Don't start a server
-	currentPort = 14244
+	setCurrentPortLocked(14244)
 
-	result := GetServerName()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(GetServerName())
 
 	if !isErrorResponse(response) {
 		t.Errorf("Expected error response, got: %s", response)
@@ -985,9 +936,7 @@ func TestIsServerRunning_True(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := IsServerRunning()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(IsServerRunning())
 
 	if response != "true" {
 		t.Errorf("Expected 'true', got: %s", response)
@@ -999,11 +948,9 @@ func TestIsServerRunning_True(t *testing.T) {
 // TestIsServerRunning_False tests when server is not running.
 func TestIsServerRunning_False(t *testing.T) {
 	// Don't start a server
-	currentPort = 14246
+	setCurrentPortLocked(14246)
 
-	result := IsServerRunning()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(IsServerRunning())
 
 	if response != "false" {
 		t.Errorf("Expected 'false', got: %s", response)
@@ -1018,9 +965,7 @@ func TestIsServerRunning_AfterShutdown(t *testing.T) {
 	srv := startTestServer(t, port)
 
 	// Verify it's running first
-	result := IsServerRunning()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(IsServerRunning())
 
 	if response != "true" {
 		t.Errorf("Expected 'true' before shutdown, got: %s", response)
@@ -1030,9 +975,7 @@ func TestIsServerRunning_AfterShutdown(t *testing.T) {
 	stopTestServer(t, srv, port)
 
 	// Now it should return false
-	result = IsServerRunning()
-	response = C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response = goStringFree(IsServerRunning())
 
 	if response != "false" {
 		t.Errorf("Expected 'false' after shutdown, got: %s", response)
@@ -1047,9 +990,7 @@ func TestWaitForReadyState_Success(t *testing.T) {
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := WaitForReadyState(C.int(5))
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(WaitForReadyState(cInt(5)))
 
 	if response != "true" {
 		t.Errorf("Expected 'true' (ready), got: %s", response)
@@ -1061,11 +1002,9 @@ func TestWaitForReadyState_Success(t *testing.T) {
 // TestWaitForReadyState_Timeout tests timeout behavior.
 func TestWaitForReadyState_ServerNotRunning(t *testing.T) {
 	// Don't start a server
-	currentPort = 14249
+	setCurrentPortLocked(14249)
 
-	result := WaitForReadyState(C.int(1))
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(WaitForReadyState(cInt(1)))
 
 	if !isErrorResponse(response) {
 		t.Errorf("Expected error response, got: %s", response)
@@ -1076,15 +1015,78 @@ func TestWaitForReadyState_ServerNotRunning(t *testing.T) {
 	}
 }
 
+// TestGracefulShutdown_CleanDrain tests that shutdown reports a clean drain
+// once all clients have disconnected before the deadline.
+func TestGracefulShutdown_CleanDrain(t *testing.T) {
+	port := 14260
+	startTestServer(t, port)
+
+	response := goStringFree(GracefulShutdown(cInt(5)))
+
+	var parsed GracefulShutdownResult
+	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
+		t.Fatalf("Expected JSON result, got %q: %v", response, err)
+	}
+	if !parsed.Drained || parsed.TimedOut || parsed.RemainingClients != 0 {
+		t.Errorf("Expected a clean drain, got: %+v", parsed)
+	}
+
+	running := goString(IsServerRunning())
+	if running != "false" {
+		t.Errorf("Expected server not running after graceful shutdown, got: %s", running)
+	}
+}
+
+// TestGracefulShutdown_TimeoutWithSlowSubscriber tests that a client still
+// connected when the deadline expires is reported as a timeout, not an error.
+func TestGracefulShutdown_TimeoutWithSlowSubscriber(t *testing.T) {
+	port := 14261
+	srv := startTestServer(t, port)
+
+	conn := dialRawClient(t, srv.Addr().String())
+	defer conn.Close()
+
+	response := goStringFree(GracefulShutdown(cInt(1)))
+
+	var parsed GracefulShutdownResult
+	if err := json.Unmarshal([]byte(response), &parsed); err != nil {
+		t.Fatalf("Expected JSON result, got %q: %v", response, err)
+	}
+	if parsed.Drained || !parsed.TimedOut || parsed.RemainingClients == 0 {
+		t.Errorf("Expected a timed-out drain with a residual client, got: %+v", parsed)
+	}
+
+	running := goString(IsServerRunning())
+	if running != "false" {
+		t.Errorf("Expected server not running after forced shutdown, got: %s", running)
+	}
+}
+
+// TestGracefulShutdown_Idempotent tests that a second call after the server
+// is already down reports a drained result instead of erroring.
+func TestGracefulShutdown_Idempotent(t *testing.T) {
+	port := 14262
+	startTestServer(t, port)
+
+	first := goString(GracefulShutdown(cInt(5)))
+	second := goString(GracefulShutdown(cInt(5)))
+
+	var parsed GracefulShutdownResult
+	if err := json.Unmarshal([]byte(second), &parsed); err != nil {
+		t.Fatalf("Expected JSON result on second call, got %q (first call was %q): %v", second, first, err)
+	}
+	if !parsed.Drained || parsed.TimedOut {
+		t.Errorf("Expected second shutdown to be a no-op drained result, got: %+v", parsed)
+	}
+}
+
 // TestIsJetStreamEnabled_WithoutJetStream tests when JetStream is not enabled.
 func TestIsJetStreamEnabled_WithoutJetStream(t *testing.T) {
 	port := 14250
 	srv := startTestServer(t, port)
 	defer stopTestServer(t, srv, port)
 
-	result := IsJetStreamEnabled()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(IsJetStreamEnabled())
 
 	if response != "false" {
 		t.Errorf("Expected 'false' (JetStream not enabled), got: %s", response)
@@ -1117,14 +1119,12 @@ func TestIsJetStreamEnabled_WithJetStream(t *testing.T) {
 
 	serverMu.Lock()
 	natsServers[port] = srv
-	currentPort = port
+	setCurrentPortLocked(port)
 	serverMu.Unlock()
 
 	defer stopTestServer(t, srv, port)
 
-	result := IsJetStreamEnabled()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(IsJetStreamEnabled())
 
 	if response != "true" {
 		t.Errorf("Expected 'true' (JetStream enabled), got: %s", response)
@@ -1136,11 +1136,9 @@ func TestIsJetStreamEnabled_WithJetStream(t *testing.T) {
 // TestIsJetStreamEnabled_ServerNotRunning tests when server is not running.
 func TestIsJetStreamEnabled_ServerNotRunning(t *testing.T) {
 	// Don't start a server
-	currentPort = 14252
+	setCurrentPortLocked(14252)
 
-	result := IsJetStreamEnabled()
-	response := C.GoString(result)
-	C.free(unsafe.Pointer(result))
+	response := goStringFree(IsJetStreamEnabled())
 
 	if !isErrorResponse(response) {
 		t.Errorf("Expected error response, got: %s", response)
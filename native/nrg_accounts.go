@@ -0,0 +1,199 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// nrgCapabilityTag is the server tag (set via TagServer) that a peer
+// advertises when it is upgraded to support running NRG (Raft replication)
+// traffic inside an asset account instead of the system account. A peer
+// missing this tag in its gossiped server info can't be trusted to place
+// Raft groups correctly, which is why SetAccountNRG refuses to flip the
+// cluster-wide toggle until every known peer carries it.
+const nrgCapabilityTag = "account-nrg"
+
+// nrgPingWindow bounds how long SetAccountNRG/GetAccountNRGStatus wait for
+// $SYS.REQ.SERVER.PING replies to arrive from every peer, the scatter/gather
+// pattern nats-server's own monitoring subjects use for cluster-wide probes.
+const nrgPingWindow = 750 * time.Millisecond
+
+// accountNRGEnabled is read from GetAccountNRGStatus and written from
+// SetAccountNRG, both reachable concurrently from the host, so - like
+// every other piece of shared state here (serverMu, accountProvisioningMu,
+// fastStates) - it needs a concurrency-safe type rather than a bare bool.
+var accountNRGEnabled atomic.Bool
+
+// NRGGroupPlacement reports one Raft group's current account placement.
+type NRGGroupPlacement struct {
+	Group     string `json:"group"`
+	Account   string `json:"account"`
+	Placement string `json:"placement"` // "system" or "asset"
+}
+
+// NRGStatus is GetAccountNRGStatus's response shape.
+type NRGStatus struct {
+	ClusterSupported bool                `json:"cluster_supported"`
+	CurrentPlacement string              `json:"current_placement"`
+	Groups           []NRGGroupPlacement `json:"groups"`
+}
+
+// probeNRGCapability scatters a $SYS.REQ.SERVER.PING and gathers replies for
+// up to nrgPingWindow, reporting whether every peer that answered carries
+// nrgCapabilityTag. A cluster of one (no routes) is trivially capable.
+func probeNRGCapability(srv nrgServer, nc *nats.Conn) (allCapable bool, responses int, err error) {
+	inbox := nats.NewInbox()
+	sub, err := nc.SubscribeSync(inbox)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to subscribe for peer probe: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	if err := nc.PublishRequest("$SYS.REQ.SERVER.PING", inbox, nil); err != nil {
+		return false, 0, fmt.Errorf("failed to probe peers: %w", err)
+	}
+	if err := nc.Flush(); err != nil {
+		return false, 0, fmt.Errorf("failed to flush peer probe: %w", err)
+	}
+
+	expected := srv.NumRoutes() + 1
+	capableCount := 0
+	deadline := time.Now().Add(nrgPingWindow)
+
+	for time.Now().Before(deadline) {
+		msg, err := sub.NextMsg(50 * time.Millisecond)
+		if err != nil {
+			continue
+		}
+
+		var ping struct {
+			Server struct {
+				Tags []string `json:"tags"`
+			} `json:"server"`
+		}
+		if err := json.Unmarshal(msg.Data, &ping); err != nil {
+			continue
+		}
+
+		responses++
+		if containsTag(ping.Server.Tags, nrgCapabilityTag) {
+			capableCount++
+		}
+		if responses >= expected {
+			break
+		}
+	}
+
+	if responses == 0 {
+		return false, 0, nil
+	}
+	return capableCount == responses, responses, nil
+}
+
+// nrgServer is the subset of *server.Server probeNRGCapability needs,
+// narrowed so it's easy to exercise without a real cluster in tests.
+type nrgServer interface {
+	NumRoutes() int
+}
+
+// SetAccountNRG toggles whether newly formed Raft groups should be placed
+// in an asset account rather than the system account. Enabling it first
+// verifies every known peer advertises nrgCapabilityTag; nats-server itself
+// decides a given Raft group's account at creation time, so this toggle
+// only takes effect for groups formed after it flips - it does not migrate
+// groups already running in the system account.
+//
+//export SetAccountNRG
+func SetAccountNRG(enabled C.int) *C.char {
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	if enabled == 0 {
+		accountNRGEnabled.Store(false)
+		return C.CString("OK")
+	}
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to connect for peer probe: %v", err))
+	}
+	defer nc.Close()
+
+	allCapable, responses, err := probeNRGCapability(srv, nc)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	if !allCapable {
+		return C.CString(fmt.Sprintf("ERROR: Not all peers advertise the %q capability tag (%d responded); refusing to enable account-scoped NRG to avoid splitting NRG traffic across system and asset accounts during a rolling upgrade", nrgCapabilityTag, responses))
+	}
+
+	accountNRGEnabled.Store(true)
+	return C.CString("OK")
+}
+
+// GetAccountNRGStatus reports whether the cluster is uniformly capable of
+// account-scoped NRG, the toggle's current setting, and each known Raft
+// group's account placement.
+//
+//export GetAccountNRGStatus
+func GetAccountNRGStatus() *C.char {
+	serverMu.Lock()
+	srv, exists := natsServers[currentPort]
+	serverMu.Unlock()
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	status := NRGStatus{CurrentPlacement: "system"}
+	if accountNRGEnabled.Load() {
+		status.CurrentPlacement = "asset"
+	}
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err == nil {
+		defer nc.Close()
+		if allCapable, responses, probeErr := probeNRGCapability(srv, nc); probeErr == nil && responses > 0 {
+			status.ClusterSupported = allCapable
+		}
+	}
+
+	// Note: Raftz's concrete return type isn't part of the public API we can
+	// depend on here, so its result is re-marshaled into a generic object
+	// keyed by group name and walked for an "account" field rather than
+	// referencing its Go type directly.
+	raftzBytes, err := json.Marshal(srv.Raftz(nil))
+	if err == nil {
+		var raw map[string]json.RawMessage
+		if json.Unmarshal(raftzBytes, &raw) == nil {
+			for group, entry := range raw {
+				var detail struct {
+					Account string `json:"account"`
+				}
+				json.Unmarshal(entry, &detail)
+				status.Groups = append(status.Groups, NRGGroupPlacement{
+					Group:     group,
+					Account:   detail.Account,
+					Placement: status.CurrentPlacement,
+				})
+			}
+		}
+	}
+
+	jsonBytes, err := json.Marshal(status)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal NRG status: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
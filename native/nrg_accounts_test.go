@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Test SetAccountNRG refuses to enable when no peer advertises the
+// capability tag, and that disabling always succeeds.
+func TestSetAccountNRG_RefusesWithoutCapablePeers(t *testing.T) {
+	port := 14420
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	response := goStringFree(SetAccountNRG(cInt(1)))
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error enabling account NRG without a capable peer tag")
+	}
+
+	disableResponse := goStringFree(SetAccountNRG(cInt(0)))
+	if isErrorResponse(disableResponse) {
+		t.Fatalf("Expected success disabling account NRG, got: %s", disableResponse)
+	}
+}
+
+// Test GetAccountNRGStatus reports the "system" placement by default.
+func TestGetAccountNRGStatus_DefaultsToSystemPlacement(t *testing.T) {
+	port := 14421
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	accountNRGEnabled.Store(false)
+
+	response := goStringFree(GetAccountNRGStatus())
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success getting NRG status, got: %s", response)
+	}
+
+	var status NRGStatus
+	if err := json.Unmarshal([]byte(response), &status); err != nil {
+		t.Fatalf("Failed to parse NRG status: %v", err)
+	}
+	if status.CurrentPlacement != "system" {
+		t.Errorf("Expected default placement 'system', got: %s", status.CurrentPlacement)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// writePromHelp writes the HELP/TYPE preamble for a metric family.
+func writePromHelp(sb *strings.Builder, name, help, typ string) {
+	fmt.Fprintf(sb, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(sb, "# TYPE %s %s\n", name, typ)
+}
+
+// promLabels renders a label set as `{k="v",...}`, or "" if empty.
+func promLabels(pairs ...string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		fmt.Fprintf(&sb, "%s=%q", pairs[i], pairs[i+1])
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// buildPrometheusMetrics translates Varz/AccountStatz/Jsz into Prometheus
+// text-exposition format, modeled on the metric families exposed by
+// prometheus-nats-exporter.
+func buildPrometheusMetrics(srv serverSnapshot) string {
+	var sb strings.Builder
+
+	writePromHelp(&sb, "nats_connections", "Number of active client connections", "gauge")
+	fmt.Fprintf(&sb, "nats_connections %d\n", srv.varz.Connections)
+
+	writePromHelp(&sb, "nats_total_connections", "Total connections accepted since start", "counter")
+	fmt.Fprintf(&sb, "nats_total_connections %d\n", srv.varz.TotalConnections)
+
+	writePromHelp(&sb, "nats_in_msgs", "Messages received by the server", "counter")
+	fmt.Fprintf(&sb, "nats_in_msgs %d\n", srv.varz.InMsgs)
+
+	writePromHelp(&sb, "nats_out_msgs", "Messages sent by the server", "counter")
+	fmt.Fprintf(&sb, "nats_out_msgs %d\n", srv.varz.OutMsgs)
+
+	writePromHelp(&sb, "nats_slow_consumers", "Number of slow consumers detected", "counter")
+	fmt.Fprintf(&sb, "nats_slow_consumers %d\n", srv.varz.SlowConsumers)
+
+	writePromHelp(&sb, "nats_mem_bytes", "Resident memory used by the server process", "gauge")
+	fmt.Fprintf(&sb, "nats_mem_bytes %d\n", srv.varz.Mem)
+
+	writePromHelp(&sb, "nats_cpu_percent", "CPU usage percentage of the server process", "gauge")
+	fmt.Fprintf(&sb, "nats_cpu_percent %f\n", srv.varz.CPU)
+
+	if len(srv.accountStatz) > 0 {
+		writePromHelp(&sb, "nats_account_conns", "Active connections per account", "gauge")
+		for _, acc := range srv.accountStatz {
+			fmt.Fprintf(&sb, "nats_account_conns%s %d\n", promLabels("account", acc.Account), acc.Conns)
+		}
+
+		writePromHelp(&sb, "nats_account_total_conns", "Total connections seen per account", "counter")
+		for _, acc := range srv.accountStatz {
+			fmt.Fprintf(&sb, "nats_account_total_conns%s %d\n", promLabels("account", acc.Account), acc.TotalConns)
+		}
+
+		writePromHelp(&sb, "nats_account_sent_msgs", "Messages sent per account", "counter")
+		for _, acc := range srv.accountStatz {
+			fmt.Fprintf(&sb, "nats_account_sent_msgs%s %d\n", promLabels("account", acc.Account), acc.Sent.Msgs)
+		}
+
+		writePromHelp(&sb, "nats_account_received_msgs", "Messages received per account", "counter")
+		for _, acc := range srv.accountStatz {
+			fmt.Fprintf(&sb, "nats_account_received_msgs%s %d\n", promLabels("account", acc.Account), acc.Received.Msgs)
+		}
+	}
+
+	if len(srv.jsStreams) > 0 {
+		writePromHelp(&sb, "nats_stream_messages", "Messages stored in a JetStream stream", "gauge")
+		for _, st := range srv.jsStreams {
+			fmt.Fprintf(&sb, "nats_stream_messages%s %d\n", promLabels("account", st.Account, "stream", st.Name), st.Messages)
+		}
+
+		writePromHelp(&sb, "nats_stream_bytes", "Bytes stored in a JetStream stream", "gauge")
+		for _, st := range srv.jsStreams {
+			fmt.Fprintf(&sb, "nats_stream_bytes%s %d\n", promLabels("account", st.Account, "stream", st.Name), st.Bytes)
+		}
+
+		writePromHelp(&sb, "nats_consumer_num_pending", "Pending messages for a JetStream consumer", "gauge")
+		for _, st := range srv.jsStreams {
+			for _, c := range st.Consumers {
+				fmt.Fprintf(&sb, "nats_consumer_num_pending%s %d\n",
+					promLabels("account", st.Account, "stream", st.Name, "consumer", c.Name), c.NumPending)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// accountStatzSample is the subset of AccountStat used for exposition.
+type accountStatzSample struct {
+	Account    string
+	Conns      int
+	TotalConns int
+	Sent       struct{ Msgs int64 }
+	Received   struct{ Msgs int64 }
+}
+
+// jsConsumerSample is the subset of JetStream consumer stats used for exposition.
+type jsConsumerSample struct {
+	Name       string
+	NumPending uint64
+}
+
+// jsStreamSample is the subset of JetStream stream stats used for exposition.
+type jsStreamSample struct {
+	Account   string
+	Name      string
+	Messages  uint64
+	Bytes     uint64
+	Consumers []jsConsumerSample
+}
+
+// varzSample is the subset of Varz used for exposition.
+type varzSample struct {
+	Connections      int
+	TotalConnections uint64
+	InMsgs           int64
+	OutMsgs          int64
+	SlowConsumers    int64
+	Mem              int64
+	CPU              float64
+}
+
+// serverSnapshot bundles the monitoring data needed to render metrics.
+type serverSnapshot struct {
+	varz         varzSample
+	accountStatz []accountStatzSample
+	jsStreams    []jsStreamSample
+}
+
+//export GetPrometheusMetrics
+func GetPrometheusMetrics() *C.char {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, exists := natsServers[currentPort]
+	if !exists || srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	varz, err := srv.Varz(nil)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to get server variables: %v", err))
+	}
+
+	snapshot := serverSnapshot{
+		varz: varzSample{
+			Connections:      varz.Connections,
+			TotalConnections: varz.TotalConnections,
+			InMsgs:           varz.InMsgs,
+			OutMsgs:          varz.OutMsgs,
+			SlowConsumers:    varz.SlowConsumers,
+			Mem:              varz.Mem,
+			CPU:              varz.CPU,
+		},
+	}
+
+	if statz, err := srv.AccountStatz(nil); err == nil && statz != nil {
+		for _, acc := range statz.Accounts {
+			sample := accountStatzSample{
+				Account:    acc.Account,
+				Conns:      acc.Conns,
+				TotalConns: acc.TotalConns,
+			}
+			sample.Sent.Msgs = acc.Sent.Msgs
+			sample.Received.Msgs = acc.Received.Msgs
+			snapshot.accountStatz = append(snapshot.accountStatz, sample)
+		}
+	}
+
+	if jsz, err := srv.Jsz(&server.JSzOptions{Streams: true, Consumer: true}); err == nil && jsz != nil {
+		for _, accInfo := range jsz.AccountDetails {
+			for _, st := range accInfo.Streams {
+				sample := jsStreamSample{
+					Account:  accInfo.Name,
+					Name:     st.Name,
+					Messages: st.State.Msgs,
+					Bytes:    st.State.Bytes,
+				}
+				for _, c := range st.Consumer {
+					sample.Consumers = append(sample.Consumers, jsConsumerSample{
+						Name:       c.Name,
+						NumPending: c.NumPending,
+					})
+				}
+				snapshot.jsStreams = append(snapshot.jsStreams, sample)
+			}
+		}
+	}
+
+	return C.CString(buildPrometheusMetrics(snapshot))
+}
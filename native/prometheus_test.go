@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test GetPrometheusMetrics with server running
+func TestGetPrometheusMetrics_ServerRunning(t *testing.T) {
+	port := 14260
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	response := goStringFree(GetPrometheusMetrics())
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success, got error: %s", response)
+	}
+
+	if !strings.Contains(response, "# HELP nats_connections") {
+		t.Error("Expected 'nats_connections' metric family in output")
+	}
+	if !strings.Contains(response, "# TYPE nats_connections gauge") {
+		t.Error("Expected nats_connections to be typed as a gauge")
+	}
+}
+
+// Test GetPrometheusMetrics without server
+func TestGetPrometheusMetrics_ServerNotRunning(t *testing.T) {
+	serverMu.Lock()
+	setCurrentPortLocked(99999)
+	serverMu.Unlock()
+
+	response := goStringFree(GetPrometheusMetrics())
+
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error when server not running")
+	}
+}
+
+func TestPromLabels(t *testing.T) {
+	if got := promLabels(); got != "" {
+		t.Errorf("expected empty labels, got %q", got)
+	}
+
+	got := promLabels("account", "APP", "stream", "ORDERS")
+	want := `{account="APP",stream="ORDERS"}`
+	if got != want {
+		t.Errorf("promLabels() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// Test IsMQTTEnabled/GetMQTTPort when the MQTT listener is configured.
+func TestIsMQTTEnabled_WithMQTT(t *testing.T) {
+	port := 14350
+	opts := &server.Options{
+		Host:      "127.0.0.1",
+		Port:      port,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		MQTT: server.MQTTOpts{
+			Host: "127.0.0.1",
+			Port: 14351,
+		},
+	}
+
+	srv, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatalf("Failed to create NATS server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("Server did not become ready in time")
+	}
+
+	serverMu.Lock()
+	natsServers[port] = srv
+	natsServerOpts[port] = opts
+	setCurrentPortLocked(port)
+	publishFastState(port, srv, opts.JetStream)
+	serverMu.Unlock()
+
+	defer stopTestServer(t, srv, port)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.MQTTAddr() == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	response := goStringFree(IsMQTTEnabled())
+	if response != "true" {
+		t.Errorf("Expected 'true' (MQTT enabled), got: %s", response)
+	}
+
+	portResponse := goStringFree(GetMQTTPort())
+	if portResponse != "14351" {
+		t.Errorf("Expected MQTT port '14351', got: %s", portResponse)
+	}
+}
+
+// Test IsMQTTEnabled/GetMQTTPort when MQTT is not configured.
+func TestIsMQTTEnabled_WithoutMQTT(t *testing.T) {
+	port := 14352
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	response := goStringFree(IsMQTTEnabled())
+	if response != "false" {
+		t.Errorf("Expected 'false' (MQTT not enabled), got: %s", response)
+	}
+
+	portResponse := goStringFree(GetMQTTPort())
+	if !isErrorResponse(portResponse) {
+		t.Errorf("Expected error getting MQTT port when disabled, got: %s", portResponse)
+	}
+}
+
+// Test IsWebsocketEnabled/GetWebsocketPort when the WebSocket listener is
+// configured.
+func TestIsWebsocketEnabled_WithWebsocket(t *testing.T) {
+	port := 14353
+	opts := &server.Options{
+		Host: "127.0.0.1",
+		Port: port,
+		Websocket: server.WebsocketOpts{
+			Host:  "127.0.0.1",
+			Port:  14354,
+			NoTLS: true,
+		},
+	}
+
+	srv, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatalf("Failed to create NATS server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("Server did not become ready in time")
+	}
+
+	serverMu.Lock()
+	natsServers[port] = srv
+	natsServerOpts[port] = opts
+	setCurrentPortLocked(port)
+	publishFastState(port, srv, opts.JetStream)
+	serverMu.Unlock()
+
+	defer stopTestServer(t, srv, port)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.WebsocketAddr() == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	response := goStringFree(IsWebsocketEnabled())
+	if response != "true" {
+		t.Errorf("Expected 'true' (WebSocket enabled), got: %s", response)
+	}
+
+	portResponse := goStringFree(GetWebsocketPort())
+	if portResponse != "14354" {
+		t.Errorf("Expected WebSocket port '14354', got: %s", portResponse)
+	}
+}
+
+// Test IsWebsocketEnabled when WebSocket is not configured.
+func TestIsWebsocketEnabled_WithoutWebsocket(t *testing.T) {
+	port := 14355
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	response := goStringFree(IsWebsocketEnabled())
+	if response != "false" {
+		t.Errorf("Expected 'false' (WebSocket not enabled), got: %s", response)
+	}
+}
+
+// Test that WaitForReadyStateFor waits for the WebSocket listener, not
+// just the core NATS port, before reporting ready.
+func TestWaitForReadyStateFor_WaitsForWebsocketListener(t *testing.T) {
+	port := 14356
+	opts := &server.Options{
+		Host: "127.0.0.1",
+		Port: port,
+		Websocket: server.WebsocketOpts{
+			Host:  "127.0.0.1",
+			Port:  14357,
+			NoTLS: true,
+		},
+	}
+
+	srv, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatalf("Failed to create NATS server: %v", err)
+	}
+	go srv.Start()
+
+	serverMu.Lock()
+	natsServers[port] = srv
+	natsServerOpts[port] = opts
+	setCurrentPortLocked(port)
+	publishFastState(port, srv, opts.JetStream)
+	serverMu.Unlock()
+
+	defer stopTestServer(t, srv, port)
+
+	handle := cString(strconv.Itoa(port))
+	defer cFree(handle)
+
+	response := goStringFree(WaitForReadyStateFor(handle, cInt(5)))
+
+	if response != "true" {
+		t.Errorf("Expected 'true' (ready including websocket), got: %s", response)
+	}
+
+	if srv.WebsocketAddr() == nil {
+		t.Error("Expected WebSocket listener to be up after WaitForReadyStateFor returns")
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// leafTLSConfig mirrors the subset of the cluster TLS config already parsed
+// in convertToNatsOptions, so leaf remotes can opt into TLS the same way.
+type leafTLSConfig struct {
+	TLSCert   string `json:"tls_cert"`
+	TLSKey    string `json:"tls_key"`
+	TLSCACert string `json:"tls_ca_cert"`
+	TLSVerify bool   `json:"tls_verify"`
+}
+
+// currentOptsLocked returns the running server and its last-applied options
+// for the current port. Caller must hold serverMu.
+func currentOptsLocked() (*server.Server, *server.Options, error) {
+	srv, exists := natsServers[currentPort]
+	if !exists || srv == nil {
+		return nil, nil, fmt.Errorf("server not running")
+	}
+	opts, exists := natsServerOpts[currentPort]
+	if !exists || opts == nil {
+		return nil, nil, fmt.Errorf("no cached options for running server")
+	}
+	return srv, opts, nil
+}
+
+//export AddLeafRemote
+func AddLeafRemote(remoteURL *C.char, credentialsPath *C.char, tlsJson *C.char) *C.char {
+	if remoteURL == nil {
+		return C.CString("ERROR: remote URL cannot be null")
+	}
+
+	parsedURL, err := url.Parse(C.GoString(remoteURL))
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Invalid remote URL: %v", err))
+	}
+
+	remote := &server.RemoteLeafOpts{URLs: []*url.URL{parsedURL}}
+
+	if credentialsPath != nil {
+		if cred := C.GoString(credentialsPath); cred != "" {
+			remote.Credentials = cred
+		}
+	}
+
+	if tlsJson != nil {
+		if tlsStr := C.GoString(tlsJson); tlsStr != "" {
+			var tlsCfg leafTLSConfig
+			if err := json.Unmarshal([]byte(tlsStr), &tlsCfg); err != nil {
+				return C.CString(fmt.Sprintf("ERROR: Failed to parse TLS configuration: %v", err))
+			}
+			if tlsCfg.TLSCert != "" && tlsCfg.TLSKey != "" {
+				tlsConfig, err := server.GenTLSConfig(&server.TLSConfigOpts{
+					CertFile: tlsCfg.TLSCert,
+					KeyFile:  tlsCfg.TLSKey,
+					CaFile:   tlsCfg.TLSCACert,
+					Verify:   tlsCfg.TLSVerify,
+				})
+				if err != nil {
+					return C.CString(fmt.Sprintf("ERROR: Failed to build TLS configuration: %v", err))
+				}
+				remote.TLS = true
+				remote.TLSConfig = tlsConfig
+			}
+		}
+	}
+
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, opts, err := currentOptsLocked()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	opts.LeafNode.Remotes = append(opts.LeafNode.Remotes, remote)
+	if err := srv.ReloadOptions(opts); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to reload options: %v", err))
+	}
+
+	return C.CString("OK")
+}
+
+//export RemoveLeafRemote
+func RemoveLeafRemote(remoteURL *C.char) *C.char {
+	if remoteURL == nil {
+		return C.CString("ERROR: remote URL cannot be null")
+	}
+	target := C.GoString(remoteURL)
+
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, opts, err := currentOptsLocked()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	remotes := opts.LeafNode.Remotes[:0]
+	removed := false
+	for _, remote := range opts.LeafNode.Remotes {
+		keep := true
+		for _, u := range remote.URLs {
+			if u.String() == target {
+				keep = false
+				removed = true
+				break
+			}
+		}
+		if keep {
+			remotes = append(remotes, remote)
+		}
+	}
+	if !removed {
+		return C.CString(fmt.Sprintf("ERROR: No leaf remote found for URL %q", target))
+	}
+	opts.LeafNode.Remotes = remotes
+
+	if err := srv.ReloadOptions(opts); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to reload options: %v", err))
+	}
+
+	return C.CString("OK")
+}
+
+//export AddGatewayRemote
+func AddGatewayRemote(name *C.char, urlsJson *C.char) *C.char {
+	if name == nil || urlsJson == nil {
+		return C.CString("ERROR: name and urls cannot be null")
+	}
+
+	gwName := C.GoString(name)
+	if gwName == "" {
+		return C.CString("ERROR: name cannot be empty")
+	}
+
+	var urlStrs []string
+	if err := json.Unmarshal([]byte(C.GoString(urlsJson)), &urlStrs); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse URLs: %v", err))
+	}
+
+	urls := make([]*url.URL, 0, len(urlStrs))
+	for _, urlStr := range urlStrs {
+		parsed, err := url.Parse(urlStr)
+		if err != nil {
+			return C.CString(fmt.Sprintf("ERROR: Invalid gateway URL %q: %v", urlStr, err))
+		}
+		urls = append(urls, parsed)
+	}
+
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, opts, err := currentOptsLocked()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	opts.Gateway.Gateways = append(opts.Gateway.Gateways, &server.RemoteGatewayOpts{
+		Name: gwName,
+		URLs: urls,
+	})
+
+	if err := srv.ReloadOptions(opts); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to reload options: %v", err))
+	}
+
+	return C.CString("OK")
+}
+
+//export RemoveGatewayRemote
+func RemoveGatewayRemote(name *C.char) *C.char {
+	if name == nil {
+		return C.CString("ERROR: name cannot be null")
+	}
+	target := C.GoString(name)
+
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, opts, err := currentOptsLocked()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	gateways := opts.Gateway.Gateways[:0]
+	removed := false
+	for _, gw := range opts.Gateway.Gateways {
+		if gw.Name == target {
+			removed = true
+			continue
+		}
+		gateways = append(gateways, gw)
+	}
+	if !removed {
+		return C.CString(fmt.Sprintf("ERROR: No gateway remote found with name %q", target))
+	}
+	opts.Gateway.Gateways = gateways
+
+	if err := srv.ReloadOptions(opts); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to reload options: %v", err))
+	}
+
+	return C.CString("OK")
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// startTrackedTestServer starts a server and also seeds natsServerOpts, as
+// createAndStartServer would, so remote-mutation helpers have a base to work from.
+func startTrackedTestServer(t *testing.T, port int) *server.Server {
+	opts := &server.Options{
+		Host: "127.0.0.1",
+		Port: port,
+	}
+
+	srv, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("Server not ready for connections")
+	}
+
+	serverMu.Lock()
+	natsServers[port] = srv
+	natsServerOpts[port] = opts
+	setCurrentPortLocked(port)
+	publishFastState(port, srv, opts.JetStream)
+	serverMu.Unlock()
+
+	return srv
+}
+
+// Test AddLeafRemote followed by RemoveLeafRemote
+func TestAddLeafRemote_ThenRemove(t *testing.T) {
+	port := 14300
+	srv := startTrackedTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	remoteURL := cString("nats-leaf://127.0.0.1:7422")
+	defer cFree(remoteURL)
+
+	response := goStringFree(AddLeafRemote(remoteURL, nil, nil))
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success, got error: %s", response)
+	}
+
+	removeResponse := goStringFree(RemoveLeafRemote(remoteURL))
+
+	if isErrorResponse(removeResponse) {
+		t.Fatalf("Expected success removing leaf remote, got error: %s", removeResponse)
+	}
+
+	// Removing again should fail since the remote is gone.
+	removeAgainResponse := goStringFree(RemoveLeafRemote(remoteURL))
+
+	if !isErrorResponse(removeAgainResponse) {
+		t.Fatal("Expected error removing an already-removed leaf remote")
+	}
+}
+
+// Test AddGatewayRemote followed by RemoveGatewayRemote
+func TestAddGatewayRemote_ThenRemove(t *testing.T) {
+	port := 14301
+	srv := startTrackedTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	name := cString("remote-dc")
+	defer cFree(name)
+	urls := cString(`["nats://127.0.0.1:7222"]`)
+	defer cFree(urls)
+
+	response := goStringFree(AddGatewayRemote(name, urls))
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success, got error: %s", response)
+	}
+
+	removeResponse := goStringFree(RemoveGatewayRemote(name))
+
+	if isErrorResponse(removeResponse) {
+		t.Fatalf("Expected success removing gateway remote, got error: %s", removeResponse)
+	}
+}
+
+// Test AddLeafRemote without a running server
+func TestAddLeafRemote_ServerNotRunning(t *testing.T) {
+	serverMu.Lock()
+	setCurrentPortLocked(99999)
+	serverMu.Unlock()
+
+	remoteURL := cString("nats-leaf://127.0.0.1:7422")
+	defer cFree(remoteURL)
+
+	response := goStringFree(AddLeafRemote(remoteURL, nil, nil))
+
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error when server not running")
+	}
+}
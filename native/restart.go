@@ -0,0 +1,241 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// restartJitterMax bounds the random delay RollingRestart waits after each
+// instance becomes ready before moving to the next one, so reconnecting
+// clients from the whole fleet don't all retry in the same instant.
+const restartJitterMax = 500 * time.Millisecond
+
+// RestartState is one port's progress through RollingRestart, returned by
+// GetRestartStatus so a supervisor can watch the rollout without blocking
+// on RollingRestart's own return.
+type RestartState struct {
+	State                string  `json:"state"` // draining|restarting|ready|failed
+	ConnectionsRemaining int     `json:"connections_remaining"`
+	ElapsedSeconds       float64 `json:"elapsed_seconds"`
+	Error                string  `json:"error,omitempty"`
+}
+
+var (
+	restartStatusMu sync.Mutex
+	restartStatus   = make(map[int]*RestartState)
+)
+
+func setRestartState(port int, state string, remaining int, started time.Time, errMsg string) {
+	restartStatusMu.Lock()
+	restartStatus[port] = &RestartState{
+		State:                state,
+		ConnectionsRemaining: remaining,
+		ElapsedSeconds:       time.Since(started).Seconds(),
+		Error:                errMsg,
+	}
+	restartStatusMu.Unlock()
+}
+
+// GetRestartStatus returns every port's most recent RollingRestart state as
+// JSON, keyed by port number.
+//
+//export GetRestartStatus
+func GetRestartStatus() *C.char {
+	restartStatusMu.Lock()
+	snapshot := make(map[string]*RestartState, len(restartStatus))
+	for port, state := range restartStatus {
+		snapshot[fmt.Sprintf("%d", port)] = state
+	}
+	restartStatusMu.Unlock()
+
+	jsonBytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal restart status: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// ShutdownServerByPort shuts down and forgets the instance listening on
+// port, the multi-server analog of ShutdownServer which only ever touches
+// currentPort.
+//
+//export ShutdownServerByPort
+func ShutdownServerByPort(port C.int) *C.char {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	p := int(port)
+	srv, exists := natsServers[p]
+	if !exists || srv == nil {
+		return C.CString("ERROR: No server on that port")
+	}
+
+	shutdownAndForgetLocked(p, srv)
+	if currentPort == p {
+		setCurrentPortLocked(0)
+	}
+
+	return C.CString("OK")
+}
+
+// EnterLameDuckModeByPort puts the instance listening on port into lame
+// duck mode, the multi-server analog of EnterLameDuckMode.
+//
+//export EnterLameDuckModeByPort
+func EnterLameDuckModeByPort(port C.int) *C.char {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, exists := natsServers[int(port)]
+	if !exists || srv == nil {
+		return C.CString("ERROR: No server on that port")
+	}
+
+	srv.LameDuckShutdown()
+	return C.CString("OK")
+}
+
+// enterLameDuckWithDrainTimeout puts srv into lame duck mode, first
+// reloading its LameDuckDuration/LameDuckGracePeriod options to match
+// drainTimeout. srv.LameDuckShutdown() otherwise blocks until the
+// server's own (by default much longer) LameDuckDuration elapses,
+// independent of the caller-supplied drain timeout - that left
+// RollingRestart's drainConnections call observing an already fully
+// drained/shut-down server instead of performing a bounded drain.
+func enterLameDuckWithDrainTimeout(srv *server.Server, port int, drainTimeout time.Duration) {
+	serverMu.Lock()
+	opts, hasOpts := natsServerOpts[port]
+	serverMu.Unlock()
+
+	if hasOpts && opts != nil && drainTimeout > 0 {
+		gracePeriod := drainTimeout / 10
+		if gracePeriod > 10*time.Second {
+			gracePeriod = 10 * time.Second
+		}
+
+		ldOpts := *opts
+		ldOpts.LameDuckDuration = drainTimeout
+		ldOpts.LameDuckGracePeriod = gracePeriod
+		if err := srv.ReloadOptions(&ldOpts); err == nil {
+			serverMu.Lock()
+			natsServerOpts[port] = &ldOpts
+			serverMu.Unlock()
+		}
+	}
+
+	srv.LameDuckShutdown()
+}
+
+// drainConnections polls srv's connection count until it reaches zero or
+// drainDeadline passes, recording progress in restartStatus as it goes.
+func drainConnections(srv *server.Server, port int, drainDeadline time.Time, started time.Time) int {
+	for {
+		connz, err := srv.Connz(nil)
+		remaining := 0
+		if err == nil {
+			remaining = connz.NumConns
+		}
+		setRestartState(port, "draining", remaining, started, "")
+
+		if remaining == 0 || time.Now().After(drainDeadline) {
+			return remaining
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// waitForMetaFollower polls srv's JetStream status until it reports a
+// meta-group (i.e. it has rejoined the clustered JetStream meta-group as a
+// follower) or timeout elapses.
+func waitForMetaFollower(srv *server.Server, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		jsz, err := srv.Jsz(nil)
+		if err == nil && jsz.Meta != nil {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return false
+}
+
+// RollingRestart drains and restarts every server in natsServers one at a
+// time with the options parsed from configJson (with each instance's own
+// port preserved), waiting up to drainSeconds for connections to clear and
+// for JetStream instances to rejoin the meta-group before moving on to the
+// next instance. Progress is available via GetRestartStatus while this
+// call is in flight.
+//
+//export RollingRestart
+func RollingRestart(configJson *C.char, drainSeconds C.int) *C.char {
+	if configJson == nil {
+		return C.CString("ERROR: configuration cannot be null")
+	}
+
+	var config ServerConfig
+	if err := json.Unmarshal([]byte(C.GoString(configJson)), &config); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse configuration: %v", err))
+	}
+
+	serverMu.Lock()
+	ports := make([]int, 0, len(natsServers))
+	for port := range natsServers {
+		ports = append(ports, port)
+	}
+	serverMu.Unlock()
+
+	drainTimeout := time.Duration(drainSeconds) * time.Second
+
+	for _, port := range ports {
+		started := time.Now()
+
+		serverMu.Lock()
+		srv, exists := natsServers[port]
+		serverMu.Unlock()
+		if !exists || srv == nil {
+			continue
+		}
+
+		enterLameDuckWithDrainTimeout(srv, port, drainTimeout)
+		drainConnections(srv, port, started.Add(drainTimeout), started)
+
+		setRestartState(port, "restarting", 0, started, "")
+
+		instanceConfig := config
+		instanceConfig.Port = port
+		opts := convertToNatsOptions(&instanceConfig)
+
+		if err := createAndStartServer(opts); err != nil {
+			setRestartState(port, "failed", 0, started, err.Error())
+			return C.CString(fmt.Sprintf("ERROR: Failed to restart server on port %d: %v", port, err))
+		}
+
+		serverMu.Lock()
+		newSrv := natsServers[port]
+		serverMu.Unlock()
+
+		if !newSrv.ReadyForConnections(drainTimeout) {
+			setRestartState(port, "failed", 0, started, "server did not become ready in time")
+			return C.CString(fmt.Sprintf("ERROR: Server on port %d did not become ready in time", port))
+		}
+
+		if opts.JetStream {
+			waitForMetaFollower(newSrv, drainTimeout)
+		}
+
+		setRestartState(port, "ready", 0, started, "")
+
+		time.Sleep(time.Duration(rand.Int63n(int64(restartJitterMax))))
+	}
+
+	return C.CString("OK")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Test ShutdownServerByPort and EnterLameDuckModeByPort operate on a
+// specific port without disturbing currentPort.
+func TestShutdownServerByPort_LeavesCurrentPortAlone(t *testing.T) {
+	portA := 14390
+	portB := 14391
+	startTestServer(t, portA)
+	srvB := startTestServer(t, portB)
+	defer stopTestServer(t, srvB, portB)
+
+	lameDuckResponse := goStringFree(EnterLameDuckModeByPort(cInt(portA)))
+	if isErrorResponse(lameDuckResponse) {
+		t.Fatalf("Expected success entering lame duck mode for port A, got: %s", lameDuckResponse)
+	}
+
+	shutdownResponse := goStringFree(ShutdownServerByPort(cInt(portA)))
+	if isErrorResponse(shutdownResponse) {
+		t.Fatalf("Expected success shutting down port A, got: %s", shutdownResponse)
+	}
+
+	serverMu.Lock()
+	_, stillThere := natsServers[portA]
+	_, bStillThere := natsServers[portB]
+	serverMu.Unlock()
+
+	if stillThere {
+		t.Error("Expected server on port A to be removed after ShutdownServerByPort")
+	}
+	if !bStillThere {
+		t.Error("Expected server on port B to be unaffected by shutting down port A")
+	}
+}
+
+// Test RollingRestart cycles a single standalone server through
+// draining/restarting/ready and that GetRestartStatus reflects it.
+func TestRollingRestart_SingleServer(t *testing.T) {
+	port := 14392
+	srv := startTestServer(t, port)
+	defer func() {
+		serverMu.Lock()
+		final, exists := natsServers[port]
+		serverMu.Unlock()
+		if exists {
+			stopTestServer(t, final, port)
+		} else {
+			stopTestServer(t, srv, port)
+		}
+	}()
+
+	configJson := cString(fmt.Sprintf(`{"host":"127.0.0.1","port":%d}`, port))
+	defer cFree(configJson)
+
+	response := goStringFree(RollingRestart(configJson, cInt(5)))
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success from RollingRestart, got: %s", response)
+	}
+
+	statusResponse := goStringFree(GetRestartStatus())
+
+	var status map[string]RestartState
+	if err := json.Unmarshal([]byte(statusResponse), &status); err != nil {
+		t.Fatalf("Failed to parse restart status: %v", err)
+	}
+
+	state, exists := status[fmt.Sprintf("%d", port)]
+	if !exists {
+		t.Fatalf("Expected restart status entry for port %d, got: %s", port, statusResponse)
+	}
+	if state.State != "ready" {
+		t.Errorf("Expected final state 'ready', got: %s", state.State)
+	}
+
+	serverMu.Lock()
+	restarted, exists := natsServers[port]
+	serverMu.Unlock()
+	if !exists || restarted == nil {
+		t.Fatal("Expected a running server on the restarted port")
+	}
+	if !restarted.ReadyForConnections(5 * time.Second) {
+		t.Fatal("Expected restarted server to be ready for connections")
+	}
+}
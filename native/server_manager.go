@@ -0,0 +1,145 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// StartManagedServer starts a new server from the given JSON configuration
+// and returns a handle for the lifecycle calls below (DrainManagedServer,
+// StopManagedServer, WaitManagedServerReady) and for SelectManagedServer.
+//
+// The handle is the server's listening port formatted as a string - the
+// same identity instances.go's handle-based API (StartServerInstance,
+// ShutdownInstance, resolveInstanceLocked) already uses - and the server
+// is registered into the same natsServers/natsServerOpts/fastStates
+// bookkeeping those calls and the legacy currentPort-based API all read,
+// via the shared createAndStartServer helper.
+//
+// Note: an earlier version of this file kept its own opaque "srv-N"
+// handle scheme in a separate managedServers map that was never added to
+// natsServers/fastStates, so none of the single-"current"-server features
+// added since (JetStream admin, JWT lifecycle, the HTTP gateway,
+// monitoring, etc.) could address a managed server at all - those
+// exported functions still only operate on whichever instance is
+// "current" (see setCurrentPortLocked), not on an arbitrary handle. Use
+// SelectManagedServer to make a given handle the current one before
+// calling them; multi-instance support here is lifecycle-only (start,
+// drain, stop, wait-ready, select), not per-instance feature access.
+//
+//export StartManagedServer
+func StartManagedServer(optsJson *C.char) *C.char {
+	if optsJson == nil {
+		return C.CString("ERROR: configuration cannot be null")
+	}
+
+	var config ServerConfig
+	if err := json.Unmarshal([]byte(C.GoString(optsJson)), &config); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse configuration: %v", err))
+	}
+
+	opts := convertToNatsOptions(&config)
+	if err := createAndStartServer(opts); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	response := struct {
+		Handle string `json:"handle"`
+	}{Handle: strconv.Itoa(opts.Port)}
+
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal handle: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// SelectManagedServer makes the given handle's server the implicit "current"
+// server used by the legacy currentPort-based monitoring and feature API.
+//
+//export SelectManagedServer
+func SelectManagedServer(handle *C.char) *C.char {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	_, port, err := resolveInstanceLocked(handle)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	setCurrentPortLocked(port)
+	return C.CString("OK")
+}
+
+// DrainManagedServer stops a server from accepting new connections and
+// blocks until in-flight clients and JetStream deliveries have finished
+// draining or the timeout (in milliseconds) elapses.
+//
+//export DrainManagedServer
+func DrainManagedServer(handle *C.char, timeoutMs C.int) *C.char {
+	serverMu.Lock()
+	srv, _, err := resolveInstanceLocked(handle)
+	serverMu.Unlock()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	srv.LameDuckShutdown()
+
+	deadline := time.Now().Add(time.Duration(timeoutMs) * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if srv.NumClients() == 0 {
+			return C.CString("OK")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return C.CString("ERROR: drain timed out with clients still connected")
+}
+
+// StopManagedServer shuts a managed server down and forgets its handle, the
+// same cleanup ShutdownInstance performs in instances.go.
+//
+//export StopManagedServer
+func StopManagedServer(handle *C.char, timeoutMs C.int) *C.char {
+	serverMu.Lock()
+	defer serverMu.Unlock()
+
+	srv, port, err := resolveInstanceLocked(handle)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	shutdownAndForgetLocked(port, srv)
+	if currentPort == port {
+		setCurrentPortLocked(0)
+	}
+
+	return C.CString("OK")
+}
+
+// WaitManagedServerReady blocks until the managed server is ready for
+// connections or the timeout (in milliseconds) elapses. serverMu is only
+// held long enough to resolve the handle; the wait itself runs unlocked,
+// matching WaitForReadyStateFor's locking pattern in instances.go.
+//
+//export WaitManagedServerReady
+func WaitManagedServerReady(handle *C.char, timeoutMs C.int) *C.char {
+	serverMu.Lock()
+	srv, _, err := resolveInstanceLocked(handle)
+	serverMu.Unlock()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+
+	if srv.ReadyForConnections(time.Duration(timeoutMs) * time.Millisecond) {
+		return C.CString("true")
+	}
+	return C.CString("false")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Test StartManagedServer followed by WaitManagedServerReady and StopManagedServer
+func TestStartManagedServer_Lifecycle(t *testing.T) {
+	config := cString(`{"host":"127.0.0.1","port":14270}`)
+	defer cFree(config)
+
+	response := goStringFree(StartManagedServer(config))
+
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success, got error: %s", response)
+	}
+
+	var handleResp struct {
+		Handle string `json:"handle"`
+	}
+	if err := json.Unmarshal([]byte(response), &handleResp); err != nil {
+		t.Fatalf("Failed to parse handle response: %v", err)
+	}
+	if handleResp.Handle == "" {
+		t.Fatal("Expected non-empty handle")
+	}
+
+	handle := cString(handleResp.Handle)
+	defer cFree(handle)
+
+	readyResponse := goStringFree(WaitManagedServerReady(handle, 2000))
+
+	if readyResponse != "true" {
+		t.Fatalf("Expected server to be ready, got: %s", readyResponse)
+	}
+
+	stopResponse := goStringFree(StopManagedServer(handle, 5000))
+
+	if isErrorResponse(stopResponse) {
+		t.Fatalf("Expected success stopping server, got error: %s", stopResponse)
+	}
+}
+
+// Test that operations against an unknown handle return an error
+func TestManagedServer_UnknownHandle(t *testing.T) {
+	handle := cString("does-not-exist")
+	defer cFree(handle)
+
+	response := goStringFree(WaitManagedServerReady(handle, 100))
+
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error for unknown handle")
+	}
+}
+
+// Test DrainManagedServer with no connected clients completes immediately
+func TestDrainManagedServer_NoClients(t *testing.T) {
+	config := cString(`{"host":"127.0.0.1","port":14271}`)
+	defer cFree(config)
+
+	response := goStringFree(StartManagedServer(config))
+
+	var handleResp struct {
+		Handle string `json:"handle"`
+	}
+	if err := json.Unmarshal([]byte(response), &handleResp); err != nil {
+		t.Fatalf("Failed to parse handle response: %v", err)
+	}
+
+	handle := cString(handleResp.Handle)
+	defer cFree(handle)
+	defer StopManagedServer(handle, 5000)
+
+	drainResponse := goStringFree(DrainManagedServer(handle, 2000))
+
+	if isErrorResponse(drainResponse) {
+		t.Fatalf("Expected drain to succeed with no clients, got: %s", drainResponse)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+// currentPortAtomic mirrors currentPort so hot-path reads (GetServerSnapshot
+// and friends) never have to take serverMu just to find out which instance
+// is "current". Every assignment to currentPort goes through
+// setCurrentPortLocked so the two never drift.
+var currentPortAtomic atomic.Int64
+
+// setCurrentPortLocked updates currentPort and its atomic mirror. Caller
+// must hold serverMu.
+func setCurrentPortLocked(port int) {
+	currentPort = port
+	currentPortAtomic.Store(int64(port))
+}
+
+// fastServerState is the atomic, lock-free status snapshot maintained per
+// port alongside natsServers, so hot inspection calls (IsServerRunning,
+// IsJetStreamEnabled, GetServerID, GetServerSnapshot) never contend with
+// serverMu - mirroring the atomic-leader/atomic-JS-context pattern
+// nats-server itself uses internally (see nats-server PR #4613) to keep
+// status reads off the critical path of server mutation.
+type fastServerState struct {
+	srv              atomic.Pointer[server.Server]
+	jetstreamEnabled atomic.Bool
+	serverID         atomic.Value // string
+	serverName       atomic.Value // string
+	startedAt        atomic.Value // time.Time
+}
+
+// fastStates is a sync.Map rather than a mutex-guarded map[int]*fastServerState:
+// entries are inserted/removed only on server start/shutdown, while
+// GetServerSnapshot and friends read from it on every call, which is exactly
+// the read-mostly access pattern sync.Map is optimized for.
+var fastStates sync.Map // port (int) -> *fastServerState
+
+func fastStateFor(port int) *fastServerState {
+	if existing, ok := fastStates.Load(port); ok {
+		return existing.(*fastServerState)
+	}
+	state, _ := fastStates.LoadOrStore(port, &fastServerState{})
+	return state.(*fastServerState)
+}
+
+func loadFastState(port int) (*fastServerState, bool) {
+	state, exists := fastStates.Load(port)
+	if !exists {
+		return nil, false
+	}
+	return state.(*fastServerState), true
+}
+
+// publishFastState records srv as the live instance for port, caching its
+// ID/name and JetStream enablement - a boot-time setting that can't change
+// without a restart - so later reads never need srv.Varz or serverMu.
+func publishFastState(port int, srv *server.Server, jetstreamEnabled bool) {
+	state := fastStateFor(port)
+	state.srv.Store(srv)
+	state.jetstreamEnabled.Store(jetstreamEnabled)
+	state.serverID.Store(srv.ID())
+	state.serverName.Store(srv.Name())
+	state.startedAt.Store(time.Now())
+}
+
+// clearFastState drops port's cached status once its server has been shut
+// down, so a stale pointer doesn't outlive the instance it described.
+func clearFastState(port int) {
+	fastStates.Delete(port)
+}
+
+// ServerSnapshot is GetServerSnapshot's response shape: everything a
+// dashboard polling at high rates needs about the current server in one
+// shot, none of it requiring serverMu.
+type ServerSnapshot struct {
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	Running          bool    `json:"running"`
+	JetstreamEnabled bool    `json:"jetstream_enabled"`
+	UptimeSeconds    float64 `json:"uptime_seconds"`
+}
+
+// GetServerSnapshot returns id, name, running, jetstream_enabled, and uptime
+// for the current server in one JSON blob, reading only atomics so it never
+// stalls behind account registration, system-account changes, or any other
+// serverMu holder.
+//
+//export GetServerSnapshot
+func GetServerSnapshot() *C.char {
+	port := int(currentPortAtomic.Load())
+	if port == 0 {
+		return C.CString("ERROR: Server not running")
+	}
+
+	state, exists := loadFastState(port)
+	if !exists {
+		return C.CString("ERROR: Server not running")
+	}
+	srv := state.srv.Load()
+	if srv == nil {
+		return C.CString("ERROR: Server not running")
+	}
+
+	startedAt, _ := state.startedAt.Load().(time.Time)
+
+	snapshot := ServerSnapshot{
+		Running:          srv.Running(),
+		JetstreamEnabled: state.jetstreamEnabled.Load(),
+		UptimeSeconds:    time.Since(startedAt).Seconds(),
+	}
+	if id, ok := state.serverID.Load().(string); ok {
+		snapshot.ID = id
+	}
+	if name, ok := state.serverName.Load().(string); ok {
+		snapshot.Name = name
+	}
+
+	jsonBytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal server snapshot: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
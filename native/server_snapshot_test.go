@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Test GetServerSnapshot reports id/name/running/jetstream_enabled/uptime
+// for a running server without taking serverMu.
+func TestGetServerSnapshot_ServerRunning(t *testing.T) {
+	port := 14430
+	srv := startTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	response := goStringFree(GetServerSnapshot())
+	if isErrorResponse(response) {
+		t.Fatalf("Expected success getting server snapshot, got: %s", response)
+	}
+
+	var snapshot ServerSnapshot
+	if err := json.Unmarshal([]byte(response), &snapshot); err != nil {
+		t.Fatalf("Failed to parse server snapshot: %v", err)
+	}
+	if !snapshot.Running {
+		t.Error("Expected snapshot to report the server as running")
+	}
+	if snapshot.ID == "" {
+		t.Error("Expected snapshot to report a server ID")
+	}
+	if snapshot.JetstreamEnabled {
+		t.Error("Expected JetStream disabled for a plain test server")
+	}
+}
+
+// Test GetServerSnapshot errors cleanly when no server is running.
+func TestGetServerSnapshot_ServerNotRunning(t *testing.T) {
+	serverMu.Lock()
+	setCurrentPortLocked(0)
+	serverMu.Unlock()
+
+	response := goStringFree(GetServerSnapshot())
+	if !isErrorResponse(response) {
+		t.Fatal("Expected error getting server snapshot with no server running")
+	}
+}
@@ -0,0 +1,386 @@
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// snapshotChunkTimeout bounds how long SnapshotStream/RestoreStream wait for
+// the next chunk on the wire before giving up, separate from
+// jsAdminRequestTimeout in jetstream_placement.go which only covers the
+// initial request/response handshake.
+const snapshotChunkTimeout = 30 * time.Second
+
+// restoreChunkBytes is how much of srcPath RestoreStream reads per
+// request/ack round-trip when streaming it back to the server.
+const restoreChunkBytes = 128 * 1024
+
+// SnapshotProgress is one in-flight (or just-finished) snapshot/restore
+// operation, returned by ListSnapshotProgress so a long-running backup can
+// be surfaced in the .NET UI instead of blocking on the export call.
+type SnapshotProgress struct {
+	Operation     string  `json:"operation"` // "snapshot" or "restore"
+	Stream        string  `json:"stream"`
+	BytesSent     int64   `json:"bytes_sent,omitempty"`
+	BytesReceived int64   `json:"bytes_received,omitempty"`
+	TotalBytes    int64   `json:"total_bytes,omitempty"`
+	EtaSeconds    float64 `json:"eta_seconds,omitempty"`
+	Done          bool    `json:"done"`
+	Error         string  `json:"error,omitempty"`
+}
+
+var (
+	snapshotProgressMu sync.Mutex
+	snapshotProgress   = make(map[string]*SnapshotProgress)
+)
+
+func setSnapshotProgress(key string, progress *SnapshotProgress) {
+	snapshotProgressMu.Lock()
+	snapshotProgress[key] = progress
+	snapshotProgressMu.Unlock()
+}
+
+// updateSnapshotETA recomputes progress.EtaSeconds from how much has moved
+// since started, given progress.TotalBytes. It no-ops until there's enough
+// data to extrapolate a rate from.
+func updateSnapshotETA(progress *SnapshotProgress, transferred int64, started time.Time) {
+	if progress.TotalBytes <= 0 || transferred <= 0 {
+		return
+	}
+	elapsed := time.Since(started).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	rate := float64(transferred) / elapsed
+	if rate <= 0 {
+		return
+	}
+	remaining := progress.TotalBytes - transferred
+	if remaining < 0 {
+		remaining = 0
+	}
+	progress.EtaSeconds = float64(remaining) / rate
+}
+
+// ListSnapshotProgress returns every tracked snapshot/restore operation,
+// keyed by "account/stream", as JSON.
+//
+//export ListSnapshotProgress
+func ListSnapshotProgress() *C.char {
+	snapshotProgressMu.Lock()
+	snapshot := make(map[string]*SnapshotProgress, len(snapshotProgress))
+	for key, progress := range snapshotProgress {
+		snapshot[key] = progress
+	}
+	snapshotProgressMu.Unlock()
+
+	jsonBytes, err := json.Marshal(snapshot)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal snapshot progress: %v", err))
+	}
+	return C.CString(string(jsonBytes))
+}
+
+// snapshotStreamRequest is the JSON body for $JS.API.STREAM.SNAPSHOT.<stream>.
+type snapshotStreamRequest struct {
+	DeliverSubject string `json:"deliver_subject"`
+	ChunkSize      int    `json:"chunk_size,omitempty"`
+	CheckMsgs      bool   `json:"jsck,omitempty"`
+	NoConsumers    bool   `json:"no_consumers,omitempty"`
+}
+
+type apiError struct {
+	Description string `json:"description"`
+}
+
+type snapshotStreamResponse struct {
+	State struct {
+		Bytes int64 `json:"bytes"`
+	} `json:"state"`
+	Error *apiError `json:"error"`
+}
+
+// SnapshotStream streams stream's current data to destPath via
+// $JS.API.STREAM.SNAPSHOT, writing the raw chunked tarball the server sends
+// as-is (it is not reinterpreted here - RestoreStream is what understands
+// its contents). Progress is available via ListSnapshotProgress while the
+// call is in flight.
+//
+//export SnapshotStream
+func SnapshotStream(account *C.char, stream *C.char, destPath *C.char, chunkSize C.int, checkMsgs C.int) *C.char {
+	if account == nil || stream == nil || destPath == nil {
+		return C.CString("ERROR: account, stream, and destPath cannot be null")
+	}
+
+	accountName := C.GoString(account)
+	streamName := C.GoString(stream)
+	dest := filepath.Clean(C.GoString(destPath))
+	key := accountName + "/" + streamName
+
+	nc, _, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	inbox := nats.NewInbox()
+	sub, err := nc.SubscribeSync(inbox)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to subscribe for snapshot chunks: %v", err))
+	}
+	defer sub.Unsubscribe()
+	if err := nc.Flush(); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to flush subscription: %v", err))
+	}
+
+	reqPayload, err := json.Marshal(snapshotStreamRequest{
+		DeliverSubject: inbox,
+		ChunkSize:      int(chunkSize),
+		CheckMsgs:      checkMsgs != 0,
+	})
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal snapshot request: %v", err))
+	}
+
+	respMsg, err := nc.Request(fmt.Sprintf("$JS.API.STREAM.SNAPSHOT.%s", streamName), reqPayload, jsAdminRequestTimeout)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to start snapshot: %v", err))
+	}
+
+	var snapResp snapshotStreamResponse
+	if err := json.Unmarshal(respMsg.Data, &snapResp); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse snapshot response: %v", err))
+	}
+	if snapResp.Error != nil {
+		return C.CString(fmt.Sprintf("ERROR: %s", snapResp.Error.Description))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to create destination directory: %v", err))
+	}
+	file, err := os.Create(dest)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to create %q: %v", dest, err))
+	}
+	defer file.Close()
+
+	progress := &SnapshotProgress{Operation: "snapshot", Stream: streamName, TotalBytes: snapResp.State.Bytes}
+	setSnapshotProgress(key, progress)
+	started := time.Now()
+
+	for {
+		msg, err := sub.NextMsg(snapshotChunkTimeout)
+		if err != nil {
+			progress.Error = fmt.Sprintf("timed out waiting for snapshot chunk: %v", err)
+			progress.Done = true
+			return C.CString(fmt.Sprintf("ERROR: %s", progress.Error))
+		}
+		if len(msg.Data) == 0 {
+			// Empty payload marks end-of-stream.
+			break
+		}
+		if _, err := file.Write(msg.Data); err != nil {
+			progress.Error = err.Error()
+			progress.Done = true
+			return C.CString(fmt.Sprintf("ERROR: Failed writing snapshot data: %v", err))
+		}
+		progress.BytesReceived += int64(len(msg.Data))
+		updateSnapshotETA(progress, progress.BytesReceived, started)
+		if msg.Reply != "" {
+			// Ack the chunk so the server's flow control releases the next one.
+			nc.Publish(msg.Reply, nil)
+		}
+	}
+
+	progress.Done = true
+	progress.EtaSeconds = 0
+	return C.CString("OK")
+}
+
+// peekSnapshotManifestStreamName opens path as a gzip+tar snapshot and
+// returns the stream name recorded in its embedded manifest, without
+// extracting anything else. The exact manifest entry name/shape isn't
+// public API and may drift between server versions; an unrecognized
+// archive is reported as an error rather than guessed at.
+func peekSnapshotManifestStreamName(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer file.Close()
+
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return "", fmt.Errorf("not a valid gzip archive: %w", err)
+	}
+	defer gzReader.Close()
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return "", fmt.Errorf("no manifest entry found in backup")
+		}
+		if err != nil {
+			return "", fmt.Errorf("corrupt tar archive: %w", err)
+		}
+		if filepath.Base(header.Name) != "meta.inf" {
+			continue
+		}
+
+		var manifest struct {
+			Config struct {
+				Name string `json:"name"`
+			} `json:"config"`
+		}
+		if err := json.NewDecoder(tarReader).Decode(&manifest); err != nil {
+			return "", fmt.Errorf("unrecognized manifest format: %w", err)
+		}
+		if manifest.Config.Name == "" {
+			return "", fmt.Errorf("manifest did not name a stream")
+		}
+		return manifest.Config.Name, nil
+	}
+}
+
+type restoreStreamRequest struct {
+	Config *nats.StreamConfig `json:"config"`
+}
+
+type restoreStreamResponse struct {
+	DeliverSubject string    `json:"deliver_subject"`
+	Error          *apiError `json:"error"`
+}
+
+type restoreChunkAck struct {
+	Error *apiError `json:"error"`
+}
+
+// RestoreStream restores srcPath (a backup previously produced by
+// SnapshotStream) into a stream matching streamConfigJson, via
+// $JS.API.STREAM.RESTORE. Before anything is sent to the server, srcPath's
+// embedded manifest is checked against streamConfigJson's name so a
+// mismatched or corrupted backup is refused up front rather than partially
+// materializing state.
+//
+//export RestoreStream
+func RestoreStream(account *C.char, streamConfigJson *C.char, srcPath *C.char) *C.char {
+	if account == nil || streamConfigJson == nil || srcPath == nil {
+		return C.CString("ERROR: account, streamConfigJson, and srcPath cannot be null")
+	}
+
+	var cfg nats.StreamConfig
+	if err := json.Unmarshal([]byte(C.GoString(streamConfigJson)), &cfg); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse stream configuration: %v", err))
+	}
+	if cfg.Name == "" {
+		return C.CString("ERROR: stream config must name a stream")
+	}
+
+	accountName := C.GoString(account)
+	src := filepath.Clean(C.GoString(srcPath))
+
+	manifestName, err := peekSnapshotManifestStreamName(src)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %q is not a valid snapshot backup: %v", src, err))
+	}
+	if manifestName != cfg.Name {
+		return C.CString(fmt.Sprintf("ERROR: Backup is for stream %q, refusing to restore it into %q", manifestName, cfg.Name))
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to stat %q: %v", src, err))
+	}
+	file, err := os.Open(src)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to open %q: %v", src, err))
+	}
+	defer file.Close()
+
+	nc, _, err := jsContext()
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: %v", err))
+	}
+	defer nc.Close()
+
+	reqPayload, err := json.Marshal(restoreStreamRequest{Config: &cfg})
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to marshal restore request: %v", err))
+	}
+
+	respMsg, err := nc.Request(fmt.Sprintf("$JS.API.STREAM.RESTORE.%s", cfg.Name), reqPayload, jsAdminRequestTimeout)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to start restore: %v", err))
+	}
+
+	var restoreResp restoreStreamResponse
+	if err := json.Unmarshal(respMsg.Data, &restoreResp); err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to parse restore response: %v", err))
+	}
+	if restoreResp.Error != nil {
+		return C.CString(fmt.Sprintf("ERROR: %s", restoreResp.Error.Description))
+	}
+
+	key := accountName + "/" + cfg.Name
+	progress := &SnapshotProgress{Operation: "restore", Stream: cfg.Name, TotalBytes: info.Size()}
+	setSnapshotProgress(key, progress)
+	started := time.Now()
+
+	buf := make([]byte, restoreChunkBytes)
+	for {
+		n, readErr := file.Read(buf)
+		if n > 0 {
+			ackMsg, err := nc.Request(restoreResp.DeliverSubject, buf[:n], snapshotChunkTimeout)
+			if err != nil {
+				progress.Error = fmt.Sprintf("failed to send chunk: %v", err)
+				progress.Done = true
+				return C.CString(fmt.Sprintf("ERROR: %s", progress.Error))
+			}
+			var ack restoreChunkAck
+			if err := json.Unmarshal(ackMsg.Data, &ack); err == nil && ack.Error != nil {
+				progress.Error = ack.Error.Description
+				progress.Done = true
+				return C.CString(fmt.Sprintf("ERROR: %s", ack.Error.Description))
+			}
+			progress.BytesSent += int64(n)
+			updateSnapshotETA(progress, progress.BytesSent, started)
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			progress.Error = readErr.Error()
+			progress.Done = true
+			return C.CString(fmt.Sprintf("ERROR: Failed reading backup: %v", readErr))
+		}
+	}
+
+	// Empty payload marks end-of-stream to the server, mirroring
+	// SnapshotStream's own EOF marker.
+	finalMsg, err := nc.Request(restoreResp.DeliverSubject, nil, jsAdminRequestTimeout)
+	if err != nil {
+		return C.CString(fmt.Sprintf("ERROR: Failed to finalize restore: %v", err))
+	}
+	var finalAck restoreChunkAck
+	if err := json.Unmarshal(finalMsg.Data, &finalAck); err == nil && finalAck.Error != nil {
+		return C.CString(fmt.Sprintf("ERROR: %s", finalAck.Error.Description))
+	}
+
+	progress.Done = true
+	progress.EtaSeconds = 0
+	return C.CString("OK")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Test SnapshotStream followed by RestoreStream round-trips a stream's
+// messages through a backup file, and that ListSnapshotProgress reports
+// both operations as done.
+func TestSnapshotAndRestoreStream_RoundTrip(t *testing.T) {
+	port := 14410
+	srv := startJetStreamTestServer(t, port)
+	defer stopTestServer(t, srv, port)
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("Failed to connect test client: %v", err)
+	}
+	defer nc.Close()
+
+	js, err := nc.JetStream()
+	if err != nil {
+		t.Fatalf("Failed to create JetStream context: %v", err)
+	}
+	if _, err := js.AddStream(&nats.StreamConfig{Name: "BACKUP_SRC", Subjects: []string{"backup.>"}}); err != nil {
+		t.Fatalf("Failed to create source stream: %v", err)
+	}
+	if _, err := js.Publish("backup.1", []byte("hello")); err != nil {
+		t.Fatalf("Failed to publish test message: %v", err)
+	}
+
+	backupPath := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	accountCStr := cString("$G")
+	defer cFree(accountCStr)
+	streamCStr := cString("BACKUP_SRC")
+	defer cFree(streamCStr)
+	destCStr := cString(backupPath)
+	defer cFree(destCStr)
+
+	snapResponse := goStringFree(SnapshotStream(accountCStr, streamCStr, destCStr, cInt(0), cInt(1)))
+	if isErrorResponse(snapResponse) {
+		t.Fatalf("Expected success snapshotting stream, got: %s", snapResponse)
+	}
+
+	restoreConfigJson := `{"name":"BACKUP_SRC","subjects":["backup.>"]}`
+	restoreConfigCStr := cString(restoreConfigJson)
+	defer cFree(restoreConfigCStr)
+
+	if _, err := js.DeleteStream("BACKUP_SRC"); err != nil {
+		t.Fatalf("Failed to delete source stream before restore: %v", err)
+	}
+
+	restoreResponse := goStringFree(RestoreStream(accountCStr, restoreConfigCStr, destCStr))
+	if isErrorResponse(restoreResponse) {
+		t.Fatalf("Expected success restoring stream, got: %s", restoreResponse)
+	}
+
+	info, err := js.StreamInfo("BACKUP_SRC")
+	if err != nil {
+		t.Fatalf("Expected restored stream to exist: %v", err)
+	}
+	if info.State.Msgs != 1 {
+		t.Errorf("Expected restored stream to have 1 message, got: %d", info.State.Msgs)
+	}
+
+	progressResponse := goStringFree(ListSnapshotProgress())
+
+	var progress map[string]SnapshotProgress
+	if err := json.Unmarshal([]byte(progressResponse), &progress); err != nil {
+		t.Fatalf("Failed to parse snapshot progress: %v", err)
+	}
+	entry, exists := progress["$G/BACKUP_SRC"]
+	if !exists || !entry.Done {
+		t.Errorf("Expected a done progress entry for $G/BACKUP_SRC, got: %s", progressResponse)
+	}
+}
@@ -0,0 +1,67 @@
+package spool
+
+import (
+	"sort"
+	"time"
+)
+
+// CompactionPolicy bounds how much spooled history Compact retains per
+// subject. A zero MaxAge or MaxBytes disables that particular bound;
+// whichever bound is set is enforced oldest-first.
+type CompactionPolicy struct {
+	MaxAge   time.Duration
+	MaxBytes int64
+}
+
+// Compact drops records older than Compaction.MaxAge and/or beyond
+// Compaction.MaxBytes per subject, then rewrites the WAL file to reclaim
+// the space - the periodic housekeeping pass a long-running spool needs
+// so its WAL doesn't grow forever.
+func (s *Spool) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, subject := range s.index.subjects() {
+		records := s.index.bySubject[subject]
+		records = compactByAge(records, s.opts.Compaction.MaxAge, now)
+		records = compactByBytes(records, s.opts.Compaction.MaxBytes)
+		s.index.replace(subject, records)
+	}
+
+	var all []Record
+	for _, subject := range s.index.subjects() {
+		all = append(all, s.index.bySubject[subject]...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Seq < all[j].Seq })
+
+	return s.wal.rewrite(all)
+}
+
+func compactByAge(records []Record, maxAge time.Duration, now time.Time) []Record {
+	if maxAge <= 0 {
+		return records
+	}
+	cutoff := now.Add(-maxAge)
+	start := 0
+	for start < len(records) && records[start].Timestamp.Before(cutoff) {
+		start++
+	}
+	return records[start:]
+}
+
+func compactByBytes(records []Record, maxBytes int64) []Record {
+	if maxBytes <= 0 {
+		return records
+	}
+	var total int64
+	start := len(records)
+	for i := len(records) - 1; i >= 0; i-- {
+		total += int64(len(records[i].Data))
+		if total > maxBytes {
+			break
+		}
+		start = i
+	}
+	return records[start:]
+}
@@ -0,0 +1,66 @@
+package spool
+
+import (
+	"sort"
+	"time"
+)
+
+// index is the spool's in-memory lookup structure: a per-subject,
+// Seq-ordered slice of records, rebuilt from the WAL on Open and kept
+// current by Spool.appendLocked. Replay and Tail both read from it rather
+// than the WAL file directly.
+type index struct {
+	bySubject map[string][]Record
+}
+
+func newIndex() *index {
+	return &index{bySubject: make(map[string][]Record)}
+}
+
+func (i *index) add(rec Record) {
+	i.bySubject[rec.Subject] = append(i.bySubject[rec.Subject], rec)
+}
+
+// rangeScan returns every record for subject with a timestamp within
+// [from, to], oldest first.
+func (i *index) rangeScan(subject string, from, to time.Time) []Record {
+	all := i.bySubject[subject]
+	start := sort.Search(len(all), func(idx int) bool {
+		return !all[idx].Timestamp.Before(from)
+	})
+
+	var out []Record
+	for _, rec := range all[start:] {
+		if rec.Timestamp.After(to) {
+			break
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+// tail returns the most recent n records for subject, oldest first.
+func (i *index) tail(subject string, n int) []Record {
+	all := i.bySubject[subject]
+	if n <= 0 || n > len(all) {
+		n = len(all)
+	}
+	start := len(all) - n
+	out := make([]Record, n)
+	copy(out, all[start:])
+	return out
+}
+
+// replace swaps in a new record list for subject, used by Spool.Compact
+// once age/size eviction has decided what to keep.
+func (i *index) replace(subject string, records []Record) {
+	i.bySubject[subject] = records
+}
+
+func (i *index) subjects() []string {
+	subjects := make([]string, 0, len(i.bySubject))
+	for subject := range i.bySubject {
+		subjects = append(subjects, subject)
+	}
+	return subjects
+}
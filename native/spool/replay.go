@@ -0,0 +1,37 @@
+package spool
+
+import (
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Replay returns every spooled record for subject with a timestamp in
+// [from, to], oldest first, as a single snapshot rather than a live feed -
+// the backlog a caller wants to stream back to a subscriber that asked to
+// catch up on history.
+func (s *Spool) Replay(subject string, from, to time.Time) []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.rangeScan(subject, from, to)
+}
+
+// Tail returns the last n spooled records for subject, oldest first - the
+// backlog a new subscriber should see before live delivery starts.
+func (s *Spool) Tail(subject string, n int) []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.tail(subject, n)
+}
+
+// TailThenLive sends Tail(subject, n) to cb, then subscribes to subject on
+// nc and forwards every subsequent live message to cb too, combining the
+// replay backlog with live delivery in one call.
+func (s *Spool) TailThenLive(nc *nats.Conn, subject string, n int, cb func(Record)) (*nats.Subscription, error) {
+	for _, rec := range s.Tail(subject, n) {
+		cb(rec)
+	}
+	return nc.Subscribe(subject, func(msg *nats.Msg) {
+		cb(Record{Subject: msg.Subject, Timestamp: time.Now(), Data: msg.Data})
+	})
+}
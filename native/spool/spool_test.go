@@ -0,0 +1,154 @@
+package spool
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// startTestServer boots an in-process NATS server on an ephemeral port for
+// Intercept's end-to-end test.
+func startTestServer(t *testing.T) *server.Server {
+	t.Helper()
+	opts := &server.Options{Host: "127.0.0.1", Port: -1}
+	srv, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatalf("Failed to create NATS server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("Server did not become ready in time")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+func openTestSpool(t *testing.T, opts Options) *Spool {
+	t.Helper()
+	if opts.Path == "" {
+		opts.Path = filepath.Join(t.TempDir(), "spool.wal")
+	}
+	sp, err := Open(opts)
+	if err != nil {
+		t.Fatalf("Failed to open spool: %v", err)
+	}
+	t.Cleanup(func() { sp.Close() })
+	return sp
+}
+
+// Test Append assigns increasing sequence numbers and Tail/Replay see the
+// appended records back.
+func TestSpool_AppendTailReplay(t *testing.T) {
+	sp := openTestSpool(t, Options{})
+
+	for i := 0; i < 3; i++ {
+		if _, err := sp.Append("orders.created", []byte("order")); err != nil {
+			t.Fatalf("Failed to append: %v", err)
+		}
+	}
+
+	tail := sp.Tail("orders.created", 2)
+	if len(tail) != 2 {
+		t.Fatalf("Expected 2 tailed records, got %d", len(tail))
+	}
+	if tail[0].Seq != 2 || tail[1].Seq != 3 {
+		t.Errorf("Expected tail seqs [2,3], got [%d,%d]", tail[0].Seq, tail[1].Seq)
+	}
+
+	replayed := sp.Replay("orders.created", time.Time{}, time.Now().Add(time.Hour))
+	if len(replayed) != 3 {
+		t.Fatalf("Expected 3 replayed records, got %d", len(replayed))
+	}
+}
+
+// Test a Spool reopened against the same path replays its prior records
+// from the WAL, surviving a simulated restart.
+func TestSpool_ReopenReplaysWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spool.wal")
+
+	sp := openTestSpool(t, Options{Path: path})
+	if _, err := sp.Append("orders.created", []byte("first")); err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+	if err := sp.Close(); err != nil {
+		t.Fatalf("Failed to close spool: %v", err)
+	}
+
+	reopened, err := Open(Options{Path: path})
+	if err != nil {
+		t.Fatalf("Failed to reopen spool: %v", err)
+	}
+	defer reopened.Close()
+
+	tail := reopened.Tail("orders.created", 10)
+	if len(tail) != 1 || string(tail[0].Data) != "first" {
+		t.Fatalf("Expected the record written before close to survive reopen, got: %+v", tail)
+	}
+
+	if _, err := reopened.Append("orders.created", []byte("second")); err != nil {
+		t.Fatalf("Failed to append after reopen: %v", err)
+	}
+	if tail := reopened.Tail("orders.created", 10); tail[len(tail)-1].Seq != 2 {
+		t.Errorf("Expected sequence numbers to continue after reopen, got: %+v", tail)
+	}
+}
+
+// Test Compact drops records older than MaxAge and rewrites the WAL
+// accordingly.
+func TestSpool_CompactByAge(t *testing.T) {
+	sp := openTestSpool(t, Options{Compaction: CompactionPolicy{MaxAge: time.Millisecond}})
+
+	if _, err := sp.Append("orders.created", []byte("stale")); err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := sp.Append("orders.created", []byte("fresh")); err != nil {
+		t.Fatalf("Failed to append: %v", err)
+	}
+
+	if err := sp.Compact(); err != nil {
+		t.Fatalf("Failed to compact: %v", err)
+	}
+
+	remaining := sp.Replay("orders.created", time.Time{}, time.Now().Add(time.Hour))
+	if len(remaining) != 1 || string(remaining[0].Data) != "fresh" {
+		t.Fatalf("Expected only the fresh record to survive compaction, got: %+v", remaining)
+	}
+}
+
+// Test Intercept appends messages published on a real NATS connection.
+func TestSpool_InterceptEndToEnd(t *testing.T) {
+	srv := startTestServer(t)
+	sp := openTestSpool(t, Options{Patterns: []string{"events.>"}})
+
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer nc.Close()
+
+	if err := sp.Intercept(nc); err != nil {
+		t.Fatalf("Failed to intercept: %v", err)
+	}
+
+	if err := nc.Publish("events.signup", []byte("payload")); err != nil {
+		t.Fatalf("Failed to publish: %v", err)
+	}
+	nc.Flush()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(sp.Tail("events.signup", 10)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for intercepted message to be spooled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	tail := sp.Tail("events.signup", 10)
+	if string(tail[0].Data) != "payload" {
+		t.Errorf("Expected spooled payload to match published data, got: %s", tail[0].Data)
+	}
+}
@@ -0,0 +1,254 @@
+package spool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// wal is the spool's on-disk write-ahead log: a flat, append-only file of
+// length-prefixed records, fsynced in batches (on FlushInterval, or when
+// rewrite runs) rather than on every write - a bounded loss window traded
+// for throughput, the same tradeoff the repo's JetStream write path makes
+// internally.
+type wal struct {
+	mu        sync.Mutex
+	file      *os.File
+	writer    *bufio.Writer
+	pending   int
+	flushInt  time.Duration
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// recordHeaderLen is the fixed-size prefix before a record's subject and
+// data bytes: a magic (2), Seq (8), UnixNano timestamp (8), and subject
+// length (4).
+const recordHeaderLen = 2 + 8 + 8 + 4
+
+// recordMagic marks the start of each frame, a cheap guard against
+// resuming a scan from a torn write after a crash.
+const recordMagic = 0x5350 // "SP"
+
+// openWAL opens (or creates) the WAL at path and replays its existing
+// well-formed records, in file order, for the caller to rebuild its
+// in-memory index from.
+func openWAL(path string, flushInterval time.Duration) (*wal, []Record, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open WAL file %q: %w", path, err)
+	}
+
+	existing, err := readAllRecords(path)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	w := &wal{
+		file:     file,
+		writer:   bufio.NewWriter(file),
+		flushInt: flushInterval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.flushLoop()
+
+	return w, existing, nil
+}
+
+func (w *wal) flushLoop() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.flushInt)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.mu.Lock()
+			w.flushLocked()
+			w.mu.Unlock()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// append writes rec's framed bytes to the buffered writer. The write is
+// only fsynced once flushLocked runs (on the flush timer or on close),
+// bounding fsync frequency instead of syncing on every record.
+func (w *wal) append(rec Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.writer.Write(encodeRecord(rec)); err != nil {
+		return fmt.Errorf("failed to append record: %w", err)
+	}
+	w.pending++
+	return nil
+}
+
+func (w *wal) flushLocked() {
+	if w.pending == 0 {
+		return
+	}
+	if err := w.writer.Flush(); err == nil {
+		w.file.Sync()
+	}
+	w.pending = 0
+}
+
+// close flushes any buffered records, fsyncs, and closes the WAL file. It
+// is safe to call more than once - later calls return the result of the
+// first, rather than closing w.stop (or the file) a second time.
+func (w *wal) close() error {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		<-w.done
+
+		w.mu.Lock()
+		w.flushLocked()
+		w.mu.Unlock()
+
+		w.closeErr = w.file.Close()
+	})
+	return w.closeErr
+}
+
+// rewrite atomically replaces the WAL's contents with records, used by
+// Spool.Compact after age/size eviction decides what to keep.
+func (w *wal) rewrite(records []Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	path := w.file.Name()
+	tmpPath := path + ".compact"
+	tmpFile, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open compaction file: %w", err)
+	}
+
+	bufWriter := bufio.NewWriter(tmpFile)
+	for _, rec := range records {
+		if _, err := bufWriter.Write(encodeRecord(rec)); err != nil {
+			tmpFile.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to write compacted record: %w", err)
+		}
+	}
+	if err := bufWriter.Flush(); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to flush compaction file: %w", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to sync compaction file: %w", err)
+	}
+	tmpFile.Close()
+
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close WAL before compaction: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to install compacted WAL: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to reopen WAL after compaction: %w", err)
+	}
+	w.file = file
+	w.writer = bufio.NewWriter(file)
+	w.pending = 0
+	return nil
+}
+
+func encodeRecord(rec Record) []byte {
+	subjectBytes := []byte(rec.Subject)
+
+	buf := make([]byte, recordHeaderLen, recordHeaderLen+len(subjectBytes)+4+len(rec.Data))
+	binary.BigEndian.PutUint16(buf[0:2], recordMagic)
+	binary.BigEndian.PutUint64(buf[2:10], rec.Seq)
+	binary.BigEndian.PutUint64(buf[10:18], uint64(rec.Timestamp.UnixNano()))
+	binary.BigEndian.PutUint32(buf[18:22], uint32(len(subjectBytes)))
+	buf = append(buf, subjectBytes...)
+
+	dataLen := make([]byte, 4)
+	binary.BigEndian.PutUint32(dataLen, uint32(len(rec.Data)))
+	buf = append(buf, dataLen...)
+	buf = append(buf, rec.Data...)
+
+	return buf
+}
+
+// readAllRecords scans path from the start, decoding every well-formed
+// frame it can. A truncated trailing frame (from a crash mid-write) is
+// silently stopped at rather than treated as corruption, since the WAL is
+// append-only and everything before it is already durable.
+func readAllRecords(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open WAL file %q: %w", path, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var records []Record
+	for {
+		rec, err := decodeRecord(reader)
+		if err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to read WAL records: %w", err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func decodeRecord(r io.Reader) (Record, error) {
+	header := make([]byte, recordHeaderLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return Record{}, err
+	}
+	if binary.BigEndian.Uint16(header[0:2]) != recordMagic {
+		return Record{}, io.ErrUnexpectedEOF
+	}
+	seq := binary.BigEndian.Uint64(header[2:10])
+	ts := int64(binary.BigEndian.Uint64(header[10:18]))
+	subjectLen := binary.BigEndian.Uint32(header[18:22])
+
+	subjectBytes := make([]byte, subjectLen)
+	if _, err := io.ReadFull(r, subjectBytes); err != nil {
+		return Record{}, io.ErrUnexpectedEOF
+	}
+
+	dataLenBytes := make([]byte, 4)
+	if _, err := io.ReadFull(r, dataLenBytes); err != nil {
+		return Record{}, io.ErrUnexpectedEOF
+	}
+	dataLen := binary.BigEndian.Uint32(dataLenBytes)
+
+	data := make([]byte, dataLen)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return Record{}, io.ErrUnexpectedEOF
+	}
+
+	return Record{
+		Subject:   string(subjectBytes),
+		Seq:       seq,
+		Timestamp: time.Unix(0, ts),
+		Data:      data,
+	}, nil
+}